@@ -0,0 +1,327 @@
+package zmapgo
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Argument is a zmap flag with its value, or a positional argument such as a target.
+type Argument struct {
+	// Flag is the flag name with dashes. Ex: "--rate". It is empty for positional arguments.
+	Flag string
+	// Value is the value of the flag or the positional argument.
+	Value string
+	// HasValue is false for flags that don't take a value. Ex: "--dryrun"
+	HasValue bool
+}
+
+// Int returns the value as an integer. Ex: "--rate 10000"
+func (a Argument) Int() (int, error) {
+	return strconv.Atoi(a.Value)
+}
+
+// List returns the comma-separated value as a list. Ex: "--output-fields saddr,sport"
+func (a Argument) List() []string {
+	if a.Value == "" {
+		return nil
+	}
+	return strings.Split(a.Value, ",")
+}
+
+// strings returns the argument as it is passed to zmap.
+func (a Argument) strings() []string {
+	if a.Flag == "" {
+		return []string{a.Value}
+	}
+	if !a.HasValue {
+		return []string{a.Flag}
+	}
+	return []string{a.Flag, a.Value}
+}
+
+// zmapBooleanFlags are the zmap flags that don't take a value.
+var zmapBooleanFlags = []string{
+	"--dryrun", "-d",
+	"--quiet", "-q",
+	"--iplayer", "-X",
+	"--vpn",
+	"--disable-syslog",
+	"--ignore-invalid-hosts",
+}
+
+// zmapShortFlags are the long names of zmap short flags.
+var zmapShortFlags = map[string]string{
+	"-p": "--target-port",
+	"-o": "--output-file",
+	"-b": "--blacklist-file",
+	"-w": "--whitelist-file",
+	"-r": "--rate",
+	"-B": "--bandwidth",
+	"-n": "--max-targets",
+	"-N": "--max-results",
+	"-t": "--max-runtime",
+	"-c": "--cooldown-time",
+	"-e": "--seed",
+	"-P": "--probes",
+	"-T": "--sender-threads",
+	"-s": "--source-port",
+	"-S": "--source-ip",
+	"-G": "--gateway-mac",
+	"-i": "--interface",
+	"-M": "--probe-module",
+	"-O": "--output-module",
+	"-f": "--output-fields",
+	"-C": "--config",
+	"-v": "--verbosity",
+	"-l": "--log-file",
+	"-L": "--log-directory",
+	"-m": "--metadata-file",
+	"-u": "--status-updates-file",
+	"-d": "--dryrun",
+	"-q": "--quiet",
+	"-X": "--iplayer",
+}
+
+// arguments is the ordered option set of a scanner.
+type arguments []Argument
+
+// parseArguments converts command line arguments into arguments.
+// "--flag=value" and "--flag value" are both accepted. A flag takes the next argument as its value,
+// unless it is a boolean flag or the next argument is a flag.
+func parseArguments(args []string) arguments {
+	var parsed arguments
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			parsed = append(parsed, Argument{Value: arg})
+			continue
+		}
+
+		if index := strings.Index(arg, "="); strings.HasPrefix(arg, "--") && index >= 0 {
+			parsed = append(parsed, Argument{Flag: arg[:index], Value: arg[index+1:], HasValue: true})
+			continue
+		}
+		if containsString(zmapBooleanFlags, arg) || i+1 >= len(args) || strings.HasPrefix(args[i+1], "-") {
+			parsed = append(parsed, Argument{Flag: arg})
+			continue
+		}
+		parsed = append(parsed, Argument{Flag: arg, Value: args[i+1], HasValue: true})
+		i++
+	}
+	return parsed
+}
+
+// normalizeFlag adds the dashes to a flag name if they are omitted. Ex: "rate" -> "--rate"
+func normalizeFlag(flag string) string {
+	if strings.HasPrefix(flag, "-") {
+		return flag
+	}
+	return "--" + flag
+}
+
+// index returns the index of the first argument with flag, or -1.
+func (a arguments) index(flag string) int {
+	for i, argument := range a {
+		if argument.Flag == flag {
+			return i
+		}
+	}
+	return -1
+}
+
+// has returns true if flag is passed.
+func (a arguments) has(flag string) bool {
+	return a.index(flag) >= 0
+}
+
+// value returns the value of the first argument with flag.
+func (a arguments) value(flag string) (string, bool) {
+	if index := a.index(flag); index >= 0 {
+		return a[index].Value, true
+	}
+	return "", false
+}
+
+// add adds a flag with value.
+func (a *arguments) add(flag string, value string) {
+	*a = append(*a, Argument{Flag: flag, Value: value, HasValue: true})
+}
+
+// addFlag adds a flag without value.
+func (a *arguments) addFlag(flag string) {
+	*a = append(*a, Argument{Flag: flag})
+}
+
+// set replaces the first argument with the same flag in place and removes the others.
+// The argument is added to the end if flag is not passed. Positional arguments are always added.
+func (a *arguments) set(argument Argument) {
+	index := a.index(argument.Flag)
+	if argument.Flag == "" || index < 0 {
+		*a = append(*a, argument)
+		return
+	}
+
+	(*a)[index] = argument
+	kept := (*a)[:index+1]
+	for _, other := range (*a)[index+1:] {
+		if other.Flag != argument.Flag {
+			kept = append(kept, other)
+		}
+	}
+	*a = kept
+}
+
+// remove removes every argument with flag. It returns false if flag is not passed.
+func (a *arguments) remove(flag string) bool {
+	var kept arguments
+	removed := false
+	for _, argument := range *a {
+		if argument.Flag == flag {
+			removed = true
+			continue
+		}
+		kept = append(kept, argument)
+	}
+	*a = kept
+	return removed
+}
+
+// clone returns a copy that does not share memory with a.
+func (a arguments) clone() arguments {
+	return append(arguments(nil), a...)
+}
+
+// longFlags returns a copy of the arguments with short flags replaced by their long names. Ex: "-p" -> "--target-port"
+func (a arguments) longFlags() arguments {
+	long := a.clone()
+	for i, argument := range long {
+		if name, ok := zmapShortFlags[argument.Flag]; ok {
+			long[i].Flag = name
+		}
+	}
+	return long
+}
+
+// strings returns the arguments as they are passed to zmap.
+func (a arguments) strings() []string {
+	var args []string
+	for _, argument := range a {
+		args = append(args, argument.strings()...)
+	}
+	return args
+}
+
+// Options returns a copy of the option set of the scanner in the order they are added.
+// Targets, ipv6 targets, blocklist and allowlist are not included, since they are passed to zmap while running.
+func (s *scanner) Options() []Argument {
+	return s.args.clone()
+}
+
+// CommandLine returns the binary path, the arguments and the targets as they are passed to zmap.
+// Arguments that are added only while running, like temporary files, are not included.
+func (s *scanner) CommandLine() []string {
+	commandLine := append([]string{s.binaryPath}, s.args.strings()...)
+	return append(commandLine, s.targets...)
+}
+
+// RemoveOption removes every argument with the given flag. Dashes can be omitted. Ex: "rate", "--rate"
+func (s *scanner) RemoveOption(flag string) error {
+	flag = normalizeFlag(flag)
+	if !s.args.remove(flag) {
+		return errors.New("argument not found")
+	}
+	return nil
+}
+
+// ReplaceOption applies option in place of the arguments it sets, instead of failing because they are
+// already added. The value is checked by the option as it is done by AddOptions. Ex:
+//
+//	scanner.ReplaceOption(WithRate("20000"))
+//
+// Targets, blocklist and allowlist are replaced as well. The scanner is not changed if the option fails.
+func (s *scanner) ReplaceOption(option Option) error {
+	// Find out what the option sets, by applying it to an empty scanner.
+	probe := s.clone()
+	probe.args = nil
+	probe.targets = nil
+	probe.ipv6Targets = nil
+	probe.blocklist = nil
+	probe.allowlist = nil
+	if err := option(probe); err != nil {
+		return err
+	}
+
+	// Apply the option again without the values it replaces, so it is checked against the other options.
+	candidate := s.clone()
+	for _, argument := range probe.args {
+		if argument.Flag != "" {
+			candidate.args.remove(argument.Flag)
+		}
+	}
+	if probe.targets != nil || probe.ipv6Targets != nil {
+		candidate.targets = nil
+		candidate.ipv6Targets = nil
+	}
+	if probe.blocklist != nil {
+		candidate.blocklist = nil
+	}
+	if probe.allowlist != nil {
+		candidate.allowlist = nil
+	}
+	kept := len(candidate.args)
+	if err := option(candidate); err != nil {
+		return err
+	}
+
+	// Replaced arguments keep their position.
+	for _, argument := range candidate.args[kept:] {
+		s.args.set(argument)
+	}
+	s.targets = candidate.targets
+	s.ipv6Targets = candidate.ipv6Targets
+	s.blocklist = candidate.blocklist
+	s.allowlist = candidate.allowlist
+	return nil
+}
+
+// Clone returns a blocking scanner with a copy of the options of the scanner.
+// The clone can be changed and run without affecting the scanner. Log and progress subscribers are not copied.
+func (s *scanner) Clone() BlockingScanner {
+	return s.clone()
+}
+
+// CloneAsync returns an async scanner with a copy of the options of the scanner. See Clone.
+func (s *scanner) CloneAsync() AsyncScanner {
+	return s.clone()
+}
+
+func (s *scanner) clone() *scanner {
+	clone := &scanner{
+		args:             s.args.clone(),
+		targets:          append([]string(nil), s.targets...),
+		ipv6Targets:      append([]string(nil), s.ipv6Targets...),
+		binaryPath:       s.binaryPath,
+		ctx:              s.ctx,
+		runner:           s.runner,
+		gracePeriod:      s.gracePeriod,
+		binaryPathPassed: s.binaryPathPassed,
+		discoveryCache:   s.discoveryCache,
+		localRunner:      s.localRunner,
+	}
+	if len(s.targets) == 0 {
+		clone.targets = nil
+	}
+	if len(s.ipv6Targets) == 0 {
+		clone.ipv6Targets = nil
+	}
+	if s.blocklist != nil {
+		clone.blocklist = NewBlocklist()
+		clone.blocklist.Merge(s.blocklist)
+	}
+	if s.allowlist != nil {
+		clone.allowlist = NewBlocklist()
+		clone.allowlist.Merge(s.allowlist)
+	}
+	return clone
+}
@@ -0,0 +1,182 @@
+package zmapgo
+
+import (
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseArguments(t *testing.T) {
+	tests := []struct {
+		testDesc          string
+		args              []string
+		expectedArguments arguments
+	}{
+		{
+			testDesc: "With Flags And Values",
+			args:     []string{"--rate", "100", "--dryrun", "-p", "80"},
+			expectedArguments: arguments{
+				{Flag: "--rate", Value: "100", HasValue: true},
+				{Flag: "--dryrun"},
+				{Flag: "-p", Value: "80", HasValue: true},
+			},
+		},
+		{
+			testDesc: "With Equal Sign",
+			args:     []string{"--output-filter=success = 1"},
+			expectedArguments: arguments{
+				{Flag: "--output-filter", Value: "success = 1", HasValue: true},
+			},
+		},
+		{
+			testDesc: "With Boolean Flag Before Target",
+			args:     []string{"--quiet", "1.1.1.1", "--vpn"},
+			expectedArguments: arguments{
+				{Flag: "--quiet"},
+				{Value: "1.1.1.1"},
+				{Flag: "--vpn"},
+			},
+		},
+		{
+			testDesc: "With Value Containing Dashes",
+			args:     []string{"--notes", "before--after", "--probe-args", "x"},
+			expectedArguments: arguments{
+				{Flag: "--notes", Value: "before--after", HasValue: true},
+				{Flag: "--probe-args", Value: "x", HasValue: true},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			assert.Equal(t, test.expectedArguments, parseArguments(test.args))
+		})
+	}
+}
+
+func TestArguments_Set(t *testing.T) {
+	t.Log("Testing set function of arguments keeps the position and removes duplicates")
+	args := parseArguments([]string{"--rate", "100", "--probes", "2", "--rate", "200"})
+	args.set(Argument{Flag: "--rate", Value: "300", HasValue: true})
+	args.set(Argument{Flag: "--seed", Value: "5", HasValue: true})
+	assert.Equal(t, []string{"--rate", "300", "--probes", "2", "--seed", "5"}, args.strings())
+}
+
+func TestArgument_Typed(t *testing.T) {
+	t.Log("Testing typed value functions of Argument")
+	rate, err := Argument{Flag: "--rate", Value: "100", HasValue: true}.Int()
+	assert.NoError(t, err)
+	assert.Equal(t, 100, rate)
+
+	assert.Equal(t, []string{"saddr", "sport"}, Argument{Flag: "--output-fields", Value: "saddr,sport", HasValue: true}.List())
+	assert.Nil(t, Argument{Flag: "--dryrun"}.List())
+}
+
+func TestGetArgument_ValueWithDashes(t *testing.T) {
+	t.Log("Testing getArgument function with a value containing \"--\"")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	s, err := newScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := s.AddOptions(WithNotes("scan -- weekly")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	value, err := s.getArgument("--notes")
+	t.Logf("Returned Error: %v", err)
+	assert.NoError(t, err)
+	assert.Equal(t, "scan -- weekly", value)
+}
+
+func TestScanner_OptionSet(t *testing.T) {
+	t.Log("Testing Options, CommandLine, RemoveOption and ReplaceOption functions")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	err = scanner.AddOptions(WithTargets("1.1.1.1"), WithTargetPort("80"), WithRate("100"), WithDryrun())
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	assert.Equal(t, []Argument{
+		{Flag: "--target-port", Value: "80", HasValue: true},
+		{Flag: "--rate", Value: "100", HasValue: true},
+		{Flag: "--dryrun"},
+	}, scanner.Options())
+
+	err = scanner.ReplaceOption(WithRate("200"))
+	t.Logf("Returned Error: %v", err)
+	assert.NoError(t, err)
+
+	err = scanner.ReplaceOption(WithRate("fast"))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when replacing with an invalid value")
+	}
+
+	err = scanner.ReplaceOption(WithTargets("2.2.2.0/24"))
+	assert.NoError(t, err)
+
+	err = scanner.RemoveOption("dryrun")
+	assert.NoError(t, err)
+	err = scanner.RemoveOption("--seed")
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when removing an option that is not added")
+	}
+
+	assert.Equal(t, []string{binary.Path, "--target-port", "80", "--rate", "200", "2.2.2.0/24"}, scanner.CommandLine())
+}
+
+func TestReplaceOption_Conflicts(t *testing.T) {
+	t.Log("Testing ReplaceOption function still checks the other options")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.1")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	err = scanner.ReplaceOption(WithIPv6SourceIP("2001:db8::1"))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when replaced option conflicts with other options")
+	}
+	assert.Equal(t, []string{binary.Path, "1.1.1.1"}, scanner.CommandLine())
+}
+
+func TestClone(t *testing.T) {
+	t.Log("Testing Clone function copies the options")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	base, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	blocklist := NewBlocklist()
+	_ = blocklist.Add("10.0.0.0/8", "")
+	if err := base.AddOptions(WithTargetPort("80"), WithRate("100"), WithBlocklist(blocklist)); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	job := base.Clone()
+	if err := job.AddOptions(WithTargets("1.1.1.1")); err != nil {
+		t.Errorf("Expected that error is not returned while adding options to clone: %v", err)
+	}
+	if err := job.ReplaceOption(WithRate("500")); err != nil {
+		t.Errorf("Expected that error is not returned while replacing option of clone: %v", err)
+	}
+	_ = blocklist.Add("192.168.0.0/16", "")
+
+	assert.Equal(t, []string{binary.Path, "--target-port", "80", "--rate", "100"}, base.CommandLine())
+	assert.Equal(t, []string{binary.Path, "--target-port", "80", "--rate", "500", "1.1.1.1"}, job.CommandLine())
+	assert.Contains(t, job.ZmapConfig(), "# blocklist: 10.0.0.0/8\n", "Expected that blocklist of clone is not changed with base blocklist")
+
+	asyncJob := base.(*scanner).CloneAsync()
+	assert.Equal(t, base.Options(), asyncJob.Options())
+}
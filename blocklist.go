@@ -0,0 +1,228 @@
+package zmapgo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"net"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ipv4SpaceSize is the number of addresses in ipv4 address space.
+const ipv4SpaceSize = uint64(1) << 32
+
+// Blocklist is a list of ipv4 networks that can be passed to zmap as blacklist or whitelist.
+// Networks from several sources are merged, duplicates are removed and overlapping networks are collapsed.
+// Use WithBlocklist or WithAllowlist to pass it to a scanner.
+type Blocklist struct {
+	entries []blocklistEntry
+}
+
+type blocklistEntry struct {
+	first   uint32
+	last    uint32
+	comment string
+}
+
+// NewBlocklist creates an empty blocklist.
+func NewBlocklist() *Blocklist {
+	return &Blocklist{}
+}
+
+// Add adds an ipv4 address or ipv4 cidr notation to the blocklist with an optional comment.
+func (b *Blocklist) Add(target string, comment string) error {
+	first, last, err := parseBlocklistTarget(target)
+	if err != nil {
+		return err
+	}
+	b.entries = append(b.entries, blocklistEntry{first: first, last: last, comment: strings.TrimSpace(comment)})
+	return nil
+}
+
+// AddReader adds the networks in zmap blacklist file format.
+// Every line contains an ipv4 address or cidr notation, optionally followed by a comment starting with "#".
+// Empty lines and lines that only contain a comment are skipped.
+func (b *Blocklist) AddReader(ioReader io.Reader) error {
+	scanner := bufio.NewScanner(ioReader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+
+		comment := ""
+		if index := strings.Index(line, "#"); index >= 0 {
+			comment = line[index+1:]
+			line = line[:index]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if err := b.Add(line, comment); err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// AddFile adds the networks in the given file. See AddReader for the file format.
+func (b *Blocklist) AddFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := b.AddReader(file); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// Merge adds every network in other to the blocklist.
+func (b *Blocklist) Merge(other *Blocklist) {
+	b.entries = append(b.entries, other.entries...)
+}
+
+// collapse returns sorted ranges without overlaps. Adjacent and overlapping ranges are joined,
+// and their comments are kept once.
+func (b *Blocklist) collapse() []blocklistEntry {
+	entries := append([]blocklistEntry{}, b.entries...)
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].first != entries[j].first {
+			return entries[i].first < entries[j].first
+		}
+		return entries[i].last > entries[j].last
+	})
+
+	var (
+		collapsed []blocklistEntry
+		comments  [][]string
+	)
+	for _, entry := range entries {
+		current := len(collapsed) - 1
+		// uint64 is used, since last+1 overflows for 255.255.255.255
+		if current >= 0 && uint64(entry.first) <= uint64(collapsed[current].last)+1 {
+			if entry.last > collapsed[current].last {
+				collapsed[current].last = entry.last
+			}
+			if entry.comment != "" && !containsString(comments[current], entry.comment) {
+				comments[current] = append(comments[current], entry.comment)
+			}
+			continue
+		}
+
+		collapsed = append(collapsed, entry)
+		if entry.comment != "" {
+			comments = append(comments, []string{entry.comment})
+		} else {
+			comments = append(comments, nil)
+		}
+	}
+
+	for i := range collapsed {
+		collapsed[i].comment = strings.Join(comments[i], "; ")
+	}
+	return collapsed
+}
+
+// Networks returns the minimal list of networks that covers the blocklist, sorted by address.
+func (b *Blocklist) Networks() []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range b.collapse() {
+		networks = append(networks, rangeToNetworks(entry.first, entry.last)...)
+	}
+	return networks
+}
+
+// Size returns the number of addresses in the blocklist. Overlapping networks are counted once.
+func (b *Blocklist) Size() uint64 {
+	var size uint64
+	for _, entry := range b.collapse() {
+		size += uint64(entry.last) - uint64(entry.first) + 1
+	}
+	return size
+}
+
+// Remaining returns the number of ipv4 addresses that are not in the blocklist.
+// If the blocklist is used as blacklist, it is the size of the address space that zmap can scan.
+func (b *Blocklist) Remaining() uint64 {
+	return ipv4SpaceSize - b.Size()
+}
+
+// WriteTo writes the blocklist in zmap blacklist file format. Comments are kept.
+func (b *Blocklist) WriteTo(ioWriter io.Writer) (int64, error) {
+	writer := bufio.NewWriter(ioWriter)
+	var written int64
+	for _, entry := range b.collapse() {
+		for _, network := range rangeToNetworks(entry.first, entry.last) {
+			line := network.String()
+			if entry.comment != "" {
+				line += " # " + entry.comment
+			}
+			n, err := writer.WriteString(line + "\n")
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, writer.Flush()
+}
+
+func (b *Blocklist) String() string {
+	var builder strings.Builder
+	_, _ = b.WriteTo(&builder)
+	return builder.String()
+}
+
+// parseBlocklistTarget returns the first and last address of an ipv4 address or cidr notation.
+func parseBlocklistTarget(target string) (uint32, uint32, error) {
+	target = strings.TrimSpace(target)
+
+	if ip := net.ParseIP(target); ip != nil {
+		if ip.To4() == nil {
+			return 0, 0, fmt.Errorf("given value of %s is not a valid ipv4 address", target)
+		}
+		address := binary.BigEndian.Uint32(ip.To4())
+		return address, address, nil
+	}
+
+	ip, network, err := net.ParseCIDR(target)
+	if err != nil || ip.To4() == nil {
+		return 0, 0, fmt.Errorf("given value of %s is not a valid ipv4 ipaddress or ipv4 cidr notation", target)
+	}
+	ones, _ := network.Mask.Size()
+	first := binary.BigEndian.Uint32(network.IP.To4())
+	last := first | uint32(ipv4SpaceSize-1)>>uint(ones)
+	return first, last, nil
+}
+
+// rangeToNetworks returns the minimal list of networks that covers the addresses from first to last.
+func rangeToNetworks(first uint32, last uint32) []*net.IPNet {
+	var networks []*net.IPNet
+	current := uint64(first)
+	end := uint64(last)
+	for current <= end {
+		// Largest block aligned to current address
+		size := 32
+		if current != 0 {
+			size = bits.TrailingZeros32(uint32(current))
+		}
+		// Shrink the block until it fits in the range
+		for size > 0 && current+(uint64(1)<<uint(size))-1 > end {
+			size--
+		}
+
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, uint32(current))
+		networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(32-size, 32)})
+		current += uint64(1) << uint(size)
+	}
+	return networks
+}
@@ -0,0 +1,175 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlocklist_AddReader(t *testing.T) {
+	t.Log("Testing AddReader function with zmap blacklist file format")
+	input := `# RFC1918
+10.0.0.0/8      # private
+192.168.0.0/16  # private
+
+127.0.0.1
+`
+	blocklist := NewBlocklist()
+	err := blocklist.AddReader(strings.NewReader(input))
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned while parsing valid blocklist")
+	}
+
+	assert.Equal(t, "10.0.0.0/8 # private\n127.0.0.1/32\n192.168.0.0/16 # private\n", blocklist.String())
+}
+
+func TestBlocklist_AddReader_WrongLine(t *testing.T) {
+	t.Log("Testing AddReader function with wrong line")
+	blocklist := NewBlocklist()
+	err := blocklist.AddReader(strings.NewReader("10.0.0.0/8\nnot-a-network\n"))
+	t.Logf("Returned Error: %v", err)
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Error("Expected that error with line number is returned while parsing wrong line")
+	}
+}
+
+func TestBlocklist_Collapse(t *testing.T) {
+	tests := []struct {
+		testDesc         string
+		targets          []string
+		expectedNetworks []string
+		expectedSize     uint64
+	}{
+		{
+			testDesc:         "With Duplicates",
+			targets:          []string{"10.0.0.0/24", "10.0.0.0/24"},
+			expectedNetworks: []string{"10.0.0.0/24"},
+			expectedSize:     256,
+		},
+		{
+			testDesc:         "With Overlapping Networks",
+			targets:          []string{"10.0.0.0/8", "10.1.0.0/16", "10.255.255.255"},
+			expectedNetworks: []string{"10.0.0.0/8"},
+			expectedSize:     1 << 24,
+		},
+		{
+			testDesc:         "With Adjacent Networks",
+			targets:          []string{"10.0.1.0/24", "10.0.0.0/24"},
+			expectedNetworks: []string{"10.0.0.0/23"},
+			expectedSize:     512,
+		},
+		{
+			testDesc:         "With Adjacent Networks That Are Not Aligned",
+			targets:          []string{"10.0.1.0/24", "10.0.2.0/24"},
+			expectedNetworks: []string{"10.0.1.0/24", "10.0.2.0/24"},
+			expectedSize:     512,
+		},
+		{
+			testDesc:         "With Whole Address Space",
+			targets:          []string{"0.0.0.0/0", "255.255.255.255"},
+			expectedNetworks: []string{"0.0.0.0/0"},
+			expectedSize:     1 << 32,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			blocklist := NewBlocklist()
+			for _, target := range test.targets {
+				if err := blocklist.Add(target, ""); err != nil {
+					t.Fatalf("Expected that error is not returned while adding %s: %v", target, err)
+				}
+			}
+
+			var networks []string
+			for _, network := range blocklist.Networks() {
+				networks = append(networks, network.String())
+			}
+			assert.Equal(t, test.expectedNetworks, networks)
+			assert.Equal(t, test.expectedSize, blocklist.Size())
+			assert.Equal(t, uint64(1<<32)-test.expectedSize, blocklist.Remaining())
+		})
+	}
+}
+
+func TestBlocklist_Merge(t *testing.T) {
+	t.Log("Testing Merge function with comments of merged networks")
+	customerA := NewBlocklist()
+	_ = customerA.Add("10.0.0.0/24", "customer a")
+	customerB := NewBlocklist()
+	_ = customerB.Add("10.0.0.128/25", "customer b")
+	_ = customerB.Add("192.168.1.1", "")
+
+	blocklist := NewBlocklist()
+	blocklist.Merge(customerA)
+	blocklist.Merge(customerB)
+
+	assert.Equal(t, "10.0.0.0/24 # customer a; customer b\n192.168.1.1/32\n", blocklist.String())
+	assert.Equal(t, uint64(257), blocklist.Size())
+}
+
+func TestBlocklist_Add_IPv6(t *testing.T) {
+	t.Log("Testing Add function with ipv6 address")
+	err := NewBlocklist().Add("2001:db8::/32", "")
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned while adding ipv6 network")
+	}
+}
+
+func TestWithBlocklist(t *testing.T) {
+	t.Log("Testing WithBlocklist and WithAllowlist functions with Run")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	blocklist := NewBlocklist()
+	_ = blocklist.Add("10.0.0.0/8", "private")
+	allowlist := NewBlocklist()
+	_ = allowlist.Add("192.0.2.0/24", "")
+
+	err = scanner.AddOptions(WithTargetPort("80"), WithBlocklist(blocklist), WithAllowlist(allowlist))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned")
+	}
+
+	args := binary.LastInvocation()
+	for _, argument := range []string{"--blacklist-file", "--whitelist-file"} {
+		path, ok := getArgumentValue(args, argument)
+		if !assert.True(t, ok, "Expected that %s is passed", argument) {
+			continue
+		}
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected that temporary %s is removed after the run", argument)
+		}
+	}
+}
+
+func TestWithBlocklist_WithBlacklistFile(t *testing.T) {
+	t.Log("Testing WithBlocklist function with blacklist file")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	err = scanner.AddOptions(WithBlacklistFile(binary.Path), WithBlocklist(NewBlocklist()))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when blocklist is used with blacklist file")
+	}
+}
@@ -0,0 +1,219 @@
+package zmapgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// profileOnlyFields are the ScanProfile fields that are not zmap flags, so they cannot be in a zmap.conf file.
+var profileOnlyFields = []string{
+	"targets",
+	"blocklist",
+	"allowlist",
+	"custom-arguments",
+}
+
+// LoadZmapConfig reads a zmap.conf file into a ScanProfile. See ParseZmapConfig for the format.
+func LoadZmapConfig(path string) (*ScanProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	profile, err := ParseZmapConfig(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// ParseZmapConfig parses a file in zmap.conf format into a ScanProfile, so that it can be inspected and
+// overridden before the options are added to a scanner with ScanProfile.Options.
+// Every line contains a long flag name without dashes and its value, optionally in double quotes.
+// Flags without value are boolean flags. Lines starting with "#" are comments. Ex:
+//
+//	probe-module tcp_synscan
+//	target-port 443
+//	output-filter "success = 1 && repeat = 0"
+//	dryrun
+//
+// Flags that have no field in ScanProfile are kept in CustomArguments.
+func ParseZmapConfig(ioReader io.Reader) (*ScanProfile, error) {
+	configArguments, err := parseZmapConfigArguments(ioReader)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &ScanProfile{}
+	for _, argument := range configArguments {
+		if !profile.setZmapFlag(strings.TrimPrefix(argument.Flag, "--"), argument.Value) {
+			profile.CustomArguments = append(profile.CustomArguments, argument.strings()...)
+		}
+	}
+	return profile, nil
+}
+
+// parseZmapConfigArguments parses a file in zmap.conf format into arguments with long flag names.
+func parseZmapConfigArguments(ioReader io.Reader) (arguments, error) {
+	var configArguments arguments
+	scanner := bufio.NewScanner(ioReader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		flag, value := line, ""
+		if index := strings.IndexAny(line, " \t="); index >= 0 {
+			flag = line[:index]
+			value = strings.TrimSpace(strings.TrimLeft(line[index:], " \t="))
+		}
+		value = unquoteConfigValue(value)
+		if flag == "" {
+			return nil, fmt.Errorf("line %d: flag name is missing", lineNumber)
+		}
+
+		if value == "" {
+			configArguments.addFlag("--" + flag)
+		} else {
+			configArguments.add("--"+flag, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return configArguments, nil
+}
+
+// effectiveArguments returns the arguments of the scanner with long flag names, followed by the arguments
+// of the config file that are not overridden by them. The config file is skipped if it cannot be read.
+func (s *scanner) effectiveArguments() arguments {
+	args := s.args.longFlags()
+	configPath, ok := args.value("--config")
+	if !ok {
+		return args
+	}
+
+	file, err := os.Open(configPath)
+	if err != nil {
+		return args
+	}
+	defer file.Close()
+
+	configArguments, err := parseZmapConfigArguments(file)
+	if err != nil {
+		return args
+	}
+	for _, argument := range configArguments {
+		if !args.has(argument.Flag) {
+			args = append(args, argument)
+		}
+	}
+	return args
+}
+
+// setZmapFlag sets the ScanProfile field whose name is flag. It returns false if there is no such field.
+// Comma-separated values are split for list fields. Ex: "output-fields saddr,sport"
+func (p *ScanProfile) setZmapFlag(flag string, value string) bool {
+	if containsString(profileOnlyFields, flag) {
+		return false
+	}
+
+	profileValue := reflect.ValueOf(p).Elem()
+	profileType := profileValue.Type()
+	for i := 0; i < profileType.NumField(); i++ {
+		if strings.Split(profileType.Field(i).Tag.Get("yaml"), ",")[0] != flag {
+			continue
+		}
+
+		field := profileValue.Field(i)
+		switch field.Kind() {
+		case reflect.Bool:
+			field.SetBool(value == "" || value == "true" || value == "1")
+		case reflect.Slice:
+			field.Set(reflect.ValueOf(strings.Split(value, ",")))
+		default:
+			field.SetString(value)
+		}
+		return true
+	}
+	return false
+}
+
+// unquoteConfigValue removes the double quotes around a zmap.conf value.
+func unquoteConfigValue(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, "\"") && strings.HasSuffix(value, "\"") {
+		return strings.ReplaceAll(value[1:len(value)-1], "\\\"", "\"")
+	}
+	return value
+}
+
+// quoteConfigValue puts double quotes around a zmap.conf value if needed.
+func quoteConfigValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " \t#\"=") {
+		return "\"" + strings.ReplaceAll(value, "\"", "\\\"") + "\""
+	}
+	return value
+}
+
+// WriteZmapConfig writes the arguments of the scanner in zmap.conf format, so that the scan can be
+// run from shell with `zmap --config <file> <targets>`.
+// Zmap does not read targets, ipv6 targets, blocklist or allowlist from the config file. They are written as comments.
+// Short flags are written with their long names. The config file passed with WithConfigFile is not included.
+func (s *scanner) WriteZmapConfig(ioWriter io.Writer) error {
+	writer := bufio.NewWriter(ioWriter)
+
+	if len(s.targets) > 0 {
+		fmt.Fprintf(writer, "# targets: %s\n", strings.Join(s.targets, " "))
+	}
+	if len(s.ipv6Targets) > 0 {
+		fmt.Fprintf(writer, "# ipv6 targets: %s\n", strings.Join(s.ipv6Targets, " "))
+	}
+	if s.blocklist != nil {
+		fmt.Fprintf(writer, "# blocklist: %s\n", networksString(s.blocklist))
+	}
+	if s.allowlist != nil {
+		fmt.Fprintf(writer, "# allowlist: %s\n", networksString(s.allowlist))
+	}
+
+	for _, argument := range s.args.longFlags() {
+		switch {
+		case argument.Flag == "--config":
+			// Config file cannot include another config file
+			continue
+		case argument.Flag == "":
+			// Positional arguments are targets
+			fmt.Fprintf(writer, "# target: %s\n", argument.Value)
+		case !strings.HasPrefix(argument.Flag, "--"):
+			// Unknown short flags cannot be written with long names
+			fmt.Fprintf(writer, "# %s\n", strings.Join(argument.strings(), " "))
+		case !argument.HasValue:
+			fmt.Fprintln(writer, strings.TrimPrefix(argument.Flag, "--"))
+		default:
+			fmt.Fprintf(writer, "%s %s\n", strings.TrimPrefix(argument.Flag, "--"), quoteConfigValue(argument.Value))
+		}
+	}
+	return writer.Flush()
+}
+
+// ZmapConfig returns the arguments of the scanner in zmap.conf format. See WriteZmapConfig.
+func (s *scanner) ZmapConfig() string {
+	var builder strings.Builder
+	_ = s.WriteZmapConfig(&builder)
+	return builder.String()
+}
+
+func networksString(blocklist *Blocklist) string {
+	var networks []string
+	for _, network := range blocklist.Networks() {
+		networks = append(networks, network.String())
+	}
+	return strings.Join(networks, " ")
+}
@@ -0,0 +1,154 @@
+package zmapgo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const zmapConfig = `### Probe Module to use
+probe-module tcp_synscan
+
+### Destination port to scan
+target-port 443
+rate=10000
+output-fields saddr,sport
+output-filter "success = 1 && repeat = 0"
+blacklist-file "/etc/zmap/blacklist.conf"
+#bandwidth 10M
+dryrun
+disable-syslog
+new-flag value
+`
+
+func TestParseZmapConfig(t *testing.T) {
+	t.Log("Testing ParseZmapConfig function")
+	profile, err := ParseZmapConfig(strings.NewReader(zmapConfig))
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+
+	assert.Equal(t, &ScanProfile{
+		ProbeModule:     "tcp_synscan",
+		TargetPort:      "443",
+		Rate:            "10000",
+		OutputFields:    []string{"saddr", "sport"},
+		OutputFilter:    "success = 1 && repeat = 0",
+		BlacklistFile:   "/etc/zmap/blacklist.conf",
+		Dryrun:          true,
+		DisableSyslog:   true,
+		CustomArguments: []string{"--new-flag", "value"},
+	}, profile)
+}
+
+func TestLoadZmapConfig(t *testing.T) {
+	t.Log("Testing LoadZmapConfig function")
+	path := filepath.Join(t.TempDir(), "zmap.conf")
+	_ = ioutil.WriteFile(path, []byte("rate 100\ntargets 1.1.1.1\n"), 0644)
+
+	profile, err := LoadZmapConfig(path)
+	if err != nil {
+		t.Fatalf("Expected that error is not returned: %v", err)
+	}
+	assert.Equal(t, "100", profile.Rate)
+	assert.Empty(t, profile.Targets, "Expected that targets are not read from config file")
+	assert.Equal(t, []string{"--targets", "1.1.1.1"}, profile.CustomArguments)
+
+	_, err = LoadZmapConfig(filepath.Join(t.TempDir(), "missing.conf"))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for missing file")
+	}
+}
+
+func TestWriteZmapConfig(t *testing.T) {
+	t.Log("Testing WriteZmapConfig function")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	blocklist := NewBlocklist()
+	_ = blocklist.Add("10.0.0.0/8", "")
+	err = scanner.AddOptions(
+		WithTargets("1.1.1.0/24", "8.8.8.8"),
+		WithTargetPort("443"),
+		WithBlocklist(blocklist),
+		WithOutputFilter("success = 1 && repeat = 0"),
+		WithNotes(`say "hi"`),
+		WithDryrun(),
+		WithCustomArguments("--rate=100", "-v", "3", "-m", "/tmp/metadata.json"),
+		WithConfigFile(writeZmapConfigFile(t, "max-targets 10\n")),
+	)
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	assert.Equal(t, `# targets: 1.1.1.0/24 8.8.8.8
+# blocklist: 10.0.0.0/8
+target-port 443
+output-filter "success = 1 && repeat = 0"
+notes "say \"hi\""
+dryrun
+rate 100
+verbosity 3
+metadata-file /tmp/metadata.json
+`, scanner.ZmapConfig())
+}
+
+// writeZmapConfigFile writes content to a temporary zmap.conf file and returns its path.
+func writeZmapConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "zmap.conf")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Expected that config file is written: %v", err)
+	}
+	return path
+}
+
+func TestZmapConfig_RoundTrip(t *testing.T) {
+	t.Log("Testing zmap.conf written by a scanner gives the same arguments when it is loaded back")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	newScannerWithBinary := func() *scanner {
+		s, err := newScanner(WithBinaryPath(binary.Path))
+		if err != nil {
+			t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+		}
+		return s
+	}
+
+	original := newScannerWithBinary()
+	err := original.AddOptions(
+		WithTargetPort("80"),
+		WithRate("1000"),
+		WithMaxTargets("10", true),
+		WithSourcePort("40000-40010"),
+		WithOutputFields([]string{"saddr", "sport"}),
+		WithOutputFilter("success = 1"),
+		WithDryrun(),
+		WithQuiet(),
+	)
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	profile, err := ParseZmapConfig(strings.NewReader(original.ZmapConfig()))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while parsing config: %v", err)
+	}
+	options, err := profile.Options()
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while building options: %v", err)
+	}
+	loaded := newScannerWithBinary()
+	if err := loaded.AddOptions(options...); err != nil {
+		t.Fatalf("Expected that error is not returned while adding loaded options: %v", err)
+	}
+
+	assert.ElementsMatch(t, original.args, loaded.args)
+}
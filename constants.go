@@ -18,3 +18,69 @@ var (
 	VerbosityLevel4 VerbosityLevel = "4"
 	VerbosityLevel5 VerbosityLevel = "5"
 )
+
+type LogLevel string
+
+var (
+	LogLevelTrace LogLevel = "TRACE"
+	LogLevelDebug LogLevel = "DEBUG"
+	LogLevelWarn  LogLevel = "WARN"
+	LogLevelInfo  LogLevel = "INFO"
+	LogLevelFatal LogLevel = "FATAL"
+)
+
+// JobStatus is the status of a ScanJob.
+type JobStatus string
+
+var (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCancelled JobStatus = "cancelled"
+)
+
+// Output field types reported by `zmap --list-output-fields`
+var (
+	OutputFieldTypeInt    = "int"
+	OutputFieldTypeString = "string"
+	OutputFieldTypeBool   = "bool"
+	OutputFieldTypeBinary = "binary"
+)
+
+// IPv6 probe modules of zmap releases that support IPv6 scanning
+var (
+	ProbeModuleIPv6TCPSynScan = "ipv6_tcp_synscan"
+	ProbeModuleIPv6UDP        = "ipv6_udp"
+	ProbeModuleIPv6UDPDNS     = "ipv6_udp_dns"
+	ProbeModuleICMPv6Echo     = "icmp6_echoscan"
+)
+
+// Feature is a zmap feature that is not supported by every zmap release.
+type Feature string
+
+var (
+	FeatureIPv6      Feature = "ipv6"
+	FeatureMultiPort Feature = "multi-port"
+	FeatureIPLayer   Feature = "iplayer"
+	FeatureListOfIPs Feature = "list-of-ips"
+	FeatureDedup     Feature = "dedup"
+)
+
+// featureVersions are the first zmap releases that support the features.
+var featureVersions = map[Feature]ZmapVersion{
+	FeatureIPv6:      {Major: 4},
+	FeatureMultiPort: {Major: 4},
+	FeatureIPLayer:   {Major: 3},
+	FeatureListOfIPs: {Major: 3},
+	FeatureDedup:     {Major: 4},
+}
+
+type DedupMethod string
+
+var (
+	DedupMethodDefault DedupMethod = "default"
+	DedupMethodNone    DedupMethod = "none"
+	DedupMethodFull    DedupMethod = "full"
+	DedupMethodWindow  DedupMethod = "window"
+)
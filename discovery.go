@@ -0,0 +1,116 @@
+package zmapgo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+)
+
+// DiscoveryCache keeps what zmap binaries print for --version, --list-probe-modules, --list-output-modules
+// and --list-output-fields of every probe module, so that zmap is not started again for every option and every scanner.
+// Entries are keyed by binary path and modification time, so a replaced binary is discovered again.
+// Failed discoveries are not cached. It is safe for concurrent use.
+//
+// Scanners that run zmap on the local host share a default cache. Scanners with a custom runner
+// use their own cache, since the binary path may point to another host. Use WithDiscoveryCache to share a
+// cache between scanners that run the same binary.
+type DiscoveryCache struct {
+	mutex   sync.Mutex
+	entries map[discoveryKey]*discoveryEntry
+}
+
+type discoveryKey struct {
+	binaryPath string
+	// modTime is zero if the binary cannot be found on the local host.
+	modTime int64
+}
+
+// discoveryEntry holds the outputs of one binary by discovery arguments.
+type discoveryEntry struct {
+	mutex   sync.Mutex
+	outputs map[string][]byte
+	// calls are the discoveries in flight, so that zmap is started once for concurrent callers.
+	calls map[string]*discoveryCall
+}
+
+// discoveryCall is a discovery in flight. done is closed when it finishes.
+type discoveryCall struct {
+	done   chan struct{}
+	output []byte
+	err    error
+}
+
+// NewDiscoveryCache creates an empty discovery cache.
+func NewDiscoveryCache() *DiscoveryCache {
+	return &DiscoveryCache{entries: map[discoveryKey]*discoveryEntry{}}
+}
+
+// defaultDiscoveryCache is shared by the scanners that run zmap on the local host.
+var defaultDiscoveryCache = NewDiscoveryCache()
+
+// entry returns the entry of the binary, creating it if needed.
+func (c *DiscoveryCache) entry(binaryPath string) *discoveryEntry {
+	key := discoveryKey{binaryPath: binaryPath}
+	if fileInfo, err := os.Stat(binaryPath); err == nil {
+		key.modTime = fileInfo.ModTime().UnixNano()
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &discoveryEntry{outputs: map[string][]byte{}, calls: map[string]*discoveryCall{}}
+		c.entries[key] = entry
+	}
+	return entry
+}
+
+// discover returns the output of zmap for discovery arguments. Zmap is started only if the output is not cached.
+// Ex: "--list-output-fields", "--probe-module icmp_echoscan --list-output-fields"
+// Concurrent callers wait for the same zmap process, but each of them returns when its own ctx is done.
+// If the process fails, waiting callers start zmap again with their own ctx.
+func (s *scanner) discover(ctx context.Context, args ...string) ([]byte, error) {
+	entry := s.discoveryCache.entry(s.binaryPath)
+	key := strings.Join(args, " ")
+
+	for {
+		entry.mutex.Lock()
+		if output, ok := entry.outputs[key]; ok {
+			entry.mutex.Unlock()
+			return output, nil
+		}
+
+		if call, ok := entry.calls[key]; ok {
+			entry.mutex.Unlock()
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			if call.err == nil {
+				return call.output, nil
+			}
+			continue
+		}
+
+		call := &discoveryCall{done: make(chan struct{})}
+		entry.calls[key] = call
+		entry.mutex.Unlock()
+
+		call.output, call.err = s.output(ctx, args...)
+
+		entry.mutex.Lock()
+		delete(entry.calls, key)
+		if call.err == nil {
+			entry.outputs[key] = call.output
+		}
+		entry.mutex.Unlock()
+		close(call.done)
+
+		if call.err != nil {
+			return nil, call.err
+		}
+		return call.output, nil
+	}
+}
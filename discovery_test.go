@@ -0,0 +1,156 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoveryCache_SharedBetweenScanners(t *testing.T) {
+	t.Log("Testing scanners of the same binary discover it once")
+	binary := zmaptest.New(t, zmaptest.Config{})
+
+	for i := 0; i < 5; i++ {
+		scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+		if err != nil {
+			t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+		}
+		err = scanner.AddOptions(WithTargetPort("80"), WithOutputFields([]string{"saddr", "sport"}))
+		t.Logf("Returned Error: %v", err)
+		if err != nil {
+			t.Error("Expected that error is not returned while adding options")
+		}
+		if _, err := scanner.Capabilities(); err != nil {
+			t.Error("Expected that error is not returned while detecting capabilities")
+		}
+	}
+	// --version, --list-probe-modules, --list-output-modules and --list-output-fields
+	assert.Len(t, binary.Invocations(), 4)
+}
+
+func TestDiscoveryCache_BinaryChanged(t *testing.T) {
+	t.Log("Testing a binary is discovered again if it is modified")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if _, err := scanner.ListProbeModules(); err != nil {
+		t.Error("Expected that error is not returned while listing probe modules")
+	}
+	assert.Len(t, binary.Invocations(), 2)
+
+	modTime := time.Now().Add(time.Hour)
+	if err := os.Chtimes(binary.Path, modTime, modTime); err != nil {
+		t.Fatalf("Expected that modification time of the binary is changed: %v", err)
+	}
+	if _, err := scanner.ListProbeModules(); err != nil {
+		t.Error("Expected that error is not returned while listing probe modules")
+	}
+	assert.Len(t, binary.Invocations(), 3)
+}
+
+func TestWithDiscoveryCache(t *testing.T) {
+	t.Log("Testing WithDiscoveryCache function")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	runner := &memoryRunner{outputs: map[string]string{
+		"--version":            "zmap 2.1.1\n",
+		"--list-probe-modules": "tcp_synscan\nicmp_echoscan\n",
+	}}
+
+	// Scanners with a custom runner don't share the default cache
+	for i := 0; i < 2; i++ {
+		scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path), WithRunner(runner))
+		if err != nil {
+			t.Fatalf("Expected that error is not returned while creating scanner with custom runner: %v", err)
+		}
+		if _, err := scanner.ListProbeModules(); err != nil {
+			t.Error("Expected that error is not returned while listing probe modules")
+		}
+	}
+	assert.Len(t, runner.commands, 4)
+
+	// Scanners with the same cache share it
+	runner.commands = nil
+	cache := NewDiscoveryCache()
+	for i := 0; i < 2; i++ {
+		scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path), WithRunner(runner), WithDiscoveryCache(cache))
+		if err != nil {
+			t.Fatalf("Expected that error is not returned while creating scanner with discovery cache: %v", err)
+		}
+		if _, err := scanner.ListProbeModules(); err != nil {
+			t.Error("Expected that error is not returned while listing probe modules")
+		}
+	}
+	assert.Len(t, runner.commands, 2)
+	assert.Len(t, binary.Invocations(), 0)
+
+	_, err := NewBlockingScanner(WithBinaryPath(binary.Path), WithDiscoveryCache(nil))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for nil discovery cache")
+	}
+
+	_, err = NewBlockingScanner(WithBinaryPath(binary.Path), WithDiscoveryCache(cache), WithDiscoveryCache(cache))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for second discovery cache")
+	}
+}
+
+// startedRunner starts processes that never exit until they are killed, and reports every start.
+type startedRunner struct {
+	started chan struct{}
+}
+
+func (r *startedRunner) Start(ctx context.Context, command *Command) (Process, error) {
+	r.started <- struct{}{}
+	return &hangingProcess{killed: make(chan struct{})}, nil
+}
+
+func TestDiscoveryCache_ConcurrentContexts(t *testing.T) {
+	t.Log("Testing a caller waiting for a discovery in flight returns when its own context is done")
+	runner := &startedRunner{started: make(chan struct{}, 10)}
+	cache := NewDiscoveryCache()
+	first, err := NewBlockingScanner(WithRunner(runner), WithDiscoveryCache(cache))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with runner: %v", err)
+	}
+	second, err := NewBlockingScanner(WithRunner(runner), WithDiscoveryCache(cache))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with runner: %v", err)
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := first.ListProbeModulesContext(firstCtx)
+		firstErr <- err
+	}()
+	<-runner.started
+
+	secondCtx, cancelSecond := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelSecond()
+	_, err = second.ListProbeModulesContext(secondCtx)
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected that context error of the waiting caller is returned")
+	}
+	assert.Len(t, runner.started, 0, "Expected that zmap is not started again while discovery is in flight")
+
+	cancelFirst()
+	select {
+	case err := <-firstErr:
+		t.Logf("Returned First Error: %v", err)
+		if !errors.Is(err, context.Canceled) {
+			t.Error("Expected that context error of the first caller is returned")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected that first caller returns after its context is cancelled")
+	}
+}
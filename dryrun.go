@@ -0,0 +1,158 @@
+package zmapgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// dryRunSeparator is printed by zmap after every packet in dryrun mode.
+const dryRunSeparator = "---"
+
+// DryRunPacket is a packet that zmap would send, printed in dryrun mode.
+// Fields which are not printed by the probe module keep their zero value.
+type DryRunPacket struct {
+	// Protocol is the name of the probe header. Ex: "tcp", "udp", "icmp"
+	Protocol string
+	Saddr    net.IP
+	Daddr    net.IP
+	Sport    uint16
+	Dport    uint16
+	TTL      uint8
+	// Flags is the tcp flags as printed by zmap.
+	Flags string
+	// Payload is the probe payload as printed by zmap.
+	Payload string
+
+	// Headers holds every printed header field by header name. Ex: Headers["tcp"]["seq"]
+	Headers map[string]map[string]string
+}
+
+// ParseDryRunPackets parses the packets printed to stdout by zmap in dryrun mode.
+// Every header is printed on its own line and packets are separated by a line of dashes. Ex:
+//
+//	tcp { source: 40123 | dest: 80 | seq: 1234 | checksum: 0X1A2B }
+//	ip { saddr: 10.0.0.1 | daddr: 1.1.1.1 | checksum: 0X3C4D }
+//	eth { shost: 00:00:00:00:00:00 | dhost: 00:00:00:00:00:00 }
+//	------------------------------------------------------
+//
+// Lines that are not headers are skipped.
+func ParseDryRunPackets(ioReader io.Reader) ([]DryRunPacket, error) {
+	var packets []DryRunPacket
+	scanner := bufio.NewScanner(ioReader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var packet *DryRunPacket
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, dryRunSeparator) && strings.Trim(line, "-") == "" {
+			if packet != nil {
+				packets = append(packets, *packet)
+				packet = nil
+			}
+			continue
+		}
+
+		name, fields, ok := parseDryRunHeader(line)
+		if !ok {
+			continue
+		}
+		if packet == nil {
+			packet = &DryRunPacket{Headers: map[string]map[string]string{}}
+		}
+		if err := packet.setHeader(name, fields); err != nil {
+			return packets, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return packets, err
+	}
+
+	// Last packet may not be followed by a separator.
+	if packet != nil {
+		packets = append(packets, *packet)
+	}
+	return packets, nil
+}
+
+// parseDryRunHeader parses a header line. Ex: "udp { source: 40123 | dest: 53 | checksum: 0X1A2B }"
+func parseDryRunHeader(line string) (string, map[string]string, bool) {
+	open := strings.Index(line, "{")
+	if open <= 0 || !strings.HasSuffix(line, "}") {
+		return "", nil, false
+	}
+	name := strings.TrimSpace(line[:open])
+	if name == "" || strings.Contains(name, " ") {
+		return "", nil, false
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(line[open+1:len(line)-1], "|") {
+		field = strings.TrimSpace(field)
+		index := strings.Index(field, ":")
+		if index <= 0 {
+			continue
+		}
+		fields[strings.TrimSpace(field[:index])] = strings.TrimSpace(field[index+1:])
+	}
+	return name, fields, true
+}
+
+// setHeader adds a parsed header to the packet and fills the typed fields.
+func (p *DryRunPacket) setHeader(name string, fields map[string]string) error {
+	p.Headers[name] = fields
+
+	switch name {
+	case "eth":
+		return nil
+	case "ip", "ip6", "ipv6":
+		if value, ok := fields["saddr"]; ok {
+			p.Saddr = net.ParseIP(value)
+		}
+		if value, ok := fields["daddr"]; ok {
+			p.Daddr = net.ParseIP(value)
+		}
+		if value, ok := fields["ttl"]; ok {
+			ttl, err := strconv.ParseUint(value, 10, 8)
+			if err != nil {
+				return fmt.Errorf("cannot parse ttl of %s header: %w", name, err)
+			}
+			p.TTL = uint8(ttl)
+		}
+		return nil
+	}
+
+	// Every other header is the probe header.
+	p.Protocol = name
+	for _, key := range []string{"source", "sport"} {
+		if value, ok := fields[key]; ok {
+			port, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return fmt.Errorf("cannot parse source port of %s header: %w", name, err)
+			}
+			p.Sport = uint16(port)
+		}
+	}
+	for _, key := range []string{"dest", "dport"} {
+		if value, ok := fields[key]; ok {
+			port, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return fmt.Errorf("cannot parse destination port of %s header: %w", name, err)
+			}
+			p.Dport = uint16(port)
+		}
+	}
+	if value, ok := fields["flags"]; ok {
+		p.Flags = value
+	}
+	if value, ok := fields["payload"]; ok {
+		p.Payload = value
+	}
+	return nil
+}
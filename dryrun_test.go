@@ -0,0 +1,123 @@
+package zmapgo
+
+import (
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dryRunOutput = `tcp { source: 40123 | dest: 80 | seq: 1815983546 | checksum: 0X4B0C }
+ip { saddr: 10.0.0.1 | daddr: 1.1.1.1 | checksum: 0X3C4D }
+eth { shost: 00:00:00:00:00:00 | dhost: 00:00:00:00:00:00 }
+------------------------------------------------------
+tcp { source: 40124 | dest: 443 | seq: 1815983547 | flags: SYN | checksum: 0X4B0D }
+ip { saddr: 10.0.0.1 | daddr: 1.1.1.2 | ttl: 255 | checksum: 0X3C4E }
+eth { shost: 00:00:00:00:00:00 | dhost: 00:00:00:00:00:00 }
+------------------------------------------------------
+`
+
+func TestParseDryRunPackets(t *testing.T) {
+	tests := []struct {
+		testDesc        string
+		output          string
+		expectedPackets []DryRunPacket
+		isErrorExpected bool
+	}{
+		{
+			testDesc: "With TCP Packets",
+			output:   dryRunOutput,
+			expectedPackets: []DryRunPacket{
+				{
+					Protocol: "tcp",
+					Saddr:    net.ParseIP("10.0.0.1"),
+					Daddr:    net.ParseIP("1.1.1.1"),
+					Sport:    40123,
+					Dport:    80,
+				},
+				{
+					Protocol: "tcp",
+					Saddr:    net.ParseIP("10.0.0.1"),
+					Daddr:    net.ParseIP("1.1.1.2"),
+					Sport:    40124,
+					Dport:    443,
+					TTL:      255,
+					Flags:    "SYN",
+				},
+			},
+		},
+		{
+			testDesc: "With UDP Packet Without Separator",
+			output:   "udp { source: 40123 | dest: 53 | payload: 1234abcd }\nip { saddr: 10.0.0.1 | daddr: 8.8.8.8 }\n",
+			expectedPackets: []DryRunPacket{
+				{
+					Protocol: "udp",
+					Saddr:    net.ParseIP("10.0.0.1"),
+					Daddr:    net.ParseIP("8.8.8.8"),
+					Sport:    40123,
+					Dport:    53,
+					Payload:  "1234abcd",
+				},
+			},
+		},
+		{
+			testDesc: "With ICMPv6 Packet And Other Lines",
+			output:   "Nov 01 00:00:00.000 [INFO] dryrun\nicmp { type: 128 | code: 0 | id: 1 | seq: 2 }\nip6 { saddr: 2001:db8::1 | daddr: 2001:db8::2 }\n---\n",
+			expectedPackets: []DryRunPacket{
+				{
+					Protocol: "icmp",
+					Saddr:    net.ParseIP("2001:db8::1"),
+					Daddr:    net.ParseIP("2001:db8::2"),
+				},
+			},
+		},
+		{
+			testDesc:        "With Empty Output",
+			output:          "",
+			expectedPackets: nil,
+		},
+		{
+			testDesc:        "With Wrong Port",
+			output:          "tcp { source: 40123 | dest: http }\n",
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			packets, err := ParseDryRunPackets(strings.NewReader(test.output))
+			t.Logf("Returned Error: %v", err)
+			if test.isErrorExpected {
+				if err == nil {
+					t.Error("Expected that error is returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Error("Expected that error is not returned")
+			}
+
+			if !assert.Len(t, packets, len(test.expectedPackets)) {
+				return
+			}
+			for i, expected := range test.expectedPackets {
+				// Headers are checked separately
+				packets[i].Headers = nil
+				assert.Equal(t, expected, packets[i])
+			}
+		})
+	}
+}
+
+func TestParseDryRunPackets_Headers(t *testing.T) {
+	t.Log("Testing ParseDryRunPackets function keeps every header field")
+	packets, err := ParseDryRunPackets(strings.NewReader(dryRunOutput))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned: %v", err)
+	}
+
+	assert.Equal(t, "1815983546", packets[0].Headers["tcp"]["seq"])
+	assert.Equal(t, "0X3C4D", packets[0].Headers["ip"]["checksum"])
+	assert.Equal(t, "00:00:00:00:00:00", packets[0].Headers["eth"]["shost"])
+}
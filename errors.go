@@ -1,6 +1,10 @@
 package zmapgo
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
 
 var (
 	// ErrNmapNotInstalled means that upon trying to manually locate zmap in the user's path,
@@ -9,5 +13,132 @@ var (
 	ErrZmapNotInstalled = errors.New("zmap binary was not found")
 
 	// ErrScanTimeout means that the provided context was done before the scanner finished its scan.
+	// It is returned wrapped, use errors.Is to check it. Results and logs gathered until zmap stopped are returned with it.
 	ErrScanTimeout = errors.New("zmap scan timed out")
+
+	// ErrJobNotDone means that the result of a ScanJob is requested before the scan is finished.
+	// Use ScanJob.Wait or ScanJob.Done to wait for it.
+	ErrJobNotDone = errors.New("scan job is not done")
+
+	// ErrMissingCapNetRaw means that zmap could not open a raw socket or capture packets.
+	// Run zmap as root or give CAP_NET_RAW capability to the binary.
+	ErrMissingCapNetRaw = errors.New("zmap does not have permission to use raw sockets (CAP_NET_RAW)")
+
+	// ErrUnknownInterface means that the network interface passed with WithInterface, or the default one, is not found.
+	ErrUnknownInterface = errors.New("zmap could not use the network interface")
+
+	// ErrGatewayMACNotFound means that zmap could not detect the MAC address of the gateway.
+	// Use WithGatewayMac to pass it manually.
+	ErrGatewayMACNotFound = errors.New("zmap could not detect gateway MAC address")
+
+	// ErrBlacklistParse means that zmap could not parse the blacklist or whitelist file.
+	ErrBlacklistParse = errors.New("zmap could not parse blacklist")
 )
+
+// ZmapExitError is returned when zmap exits with a non zero code or logs a FATAL line.
+// Use errors.Is with the sentinel errors to check the cause.
+type ZmapExitError struct {
+	// ExitCode is the exit code of zmap process. It is -1 if the process is killed after a FATAL line.
+	ExitCode int
+	// Stderr is the last lines that zmap printed to stderr, not including status updates.
+	Stderr []string
+	Fatals []LogLine
+	// Err is the cause parsed from the FATAL lines. It is one of the sentinel errors, or nil if the cause is unknown.
+	Err error
+}
+
+func (e *ZmapExitError) Error() string {
+	message := fmt.Sprintf("zmap exited with code %d", e.ExitCode)
+	switch {
+	case len(e.Fatals) > 0:
+		message += ": " + e.Fatals[len(e.Fatals)-1].Message
+	case len(e.Stderr) > 0:
+		message += ": " + e.Stderr[len(e.Stderr)-1]
+	}
+	return message
+}
+
+func (e *ZmapExitError) Unwrap() error {
+	return e.Err
+}
+
+// fatalCauses maps the sentinel errors to the texts that zmap logs for them.
+// A FATAL line matches a cause if it contains all texts of any of the groups.
+var fatalCauses = []struct {
+	err    error
+	groups [][]string
+}{
+	{
+		err: ErrMissingCapNetRaw,
+		groups: [][]string{
+			{"Operation not permitted"},
+			{"Are you root?"},
+			{"permission to capture"},
+			{"CAP_NET_RAW"},
+		},
+	},
+	{
+		err: ErrUnknownInterface,
+		groups: [][]string{
+			{"No such device"},
+			{"could not detect default IP address for"},
+			{"could not retrieve hardware address for interface"},
+			{"could not get IP address for interface"},
+		},
+	},
+	{
+		err: ErrGatewayMACNotFound,
+		groups: [][]string{
+			{"GW MAC"},
+			{"gateway MAC"},
+			{"could not detect default gateway"},
+		},
+	},
+	{
+		err: ErrBlacklistParse,
+		groups: [][]string{
+			{"blacklist", "parse"},
+			{"whitelist", "parse"},
+			{"blacklist", "invalid"},
+			{"whitelist", "invalid"},
+		},
+	},
+}
+
+// fatalCause returns the sentinel error of the first FATAL line with a known cause, or nil.
+func fatalCause(fatals []LogLine) error {
+	for _, fatal := range fatals {
+		for _, cause := range fatalCauses {
+			for _, group := range cause.groups {
+				if containsAll(fatal.Message, group) {
+					return cause.err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func containsAll(value string, texts []string) bool {
+	for _, text := range texts {
+		if !strings.Contains(value, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// stderrTailSize is the number of stderr lines kept for ZmapExitError.
+const stderrTailSize = 20
+
+// lineTail keeps the last lines added to it.
+type lineTail struct {
+	lines []string
+}
+
+func (t *lineTail) add(line string) {
+	if len(t.lines) == stderrTailSize {
+		t.lines = append(t.lines[:0], t.lines[1:]...)
+	}
+	t.lines = append(t.lines, line)
+}
@@ -0,0 +1,95 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFatalCause(t *testing.T) {
+	tests := []struct {
+		testDesc      string
+		message       string
+		expectedError error
+	}{
+		{
+			testDesc:      "Missing CAP_NET_RAW",
+			message:       "send: couldn't create socket. Are you root? Error: Operation not permitted",
+			expectedError: ErrMissingCapNetRaw,
+		},
+		{
+			testDesc:      "Unknown Interface",
+			message:       "recv: could not open device eth9: eth9: SIOCETHTOOL(ETHTOOL_GET_TS_INFO) ioctl failed: No such device",
+			expectedError: ErrUnknownInterface,
+		},
+		{
+			testDesc:      "Gateway MAC Not Found",
+			message:       "zmap: could not detect GW MAC address for 10.0.0.1 on eth0.",
+			expectedError: ErrGatewayMACNotFound,
+		},
+		{
+			testDesc:      "Blacklist Parse Error",
+			message:       "constraint: unable to parse blacklist file: /etc/zmap/blacklist.conf",
+			expectedError: ErrBlacklistParse,
+		},
+		{
+			testDesc: "Unknown Cause",
+			message:  "zmap: zero eligible addresses to scan",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			err := fatalCause([]LogLine{{LogType: "FATAL", Message: test.message}})
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.expectedError, err)
+		})
+	}
+}
+
+func TestLineTail(t *testing.T) {
+	t.Log("Testing lineTail with more lines than its size")
+	var tail lineTail
+	for i := 0; i < stderrTailSize+5; i++ {
+		tail.add(string(rune('a' + i)))
+	}
+
+	assert.Len(t, tail.lines, stderrTailSize)
+	assert.Equal(t, "f", tail.lines[0])
+}
+
+func TestRun_ZmapExitError(t *testing.T) {
+	t.Log("Testing Run function with zmap logging a FATAL line")
+	binary := zmaptest.New(t, zmaptest.Config{
+		Logs: []string{
+			zmaptest.LogLine("INFO", "zmap: started"),
+			zmaptest.LogLine("FATAL", "zmap: could not detect GW MAC address for 10.0.0.1 on eth0."),
+		},
+		ExitCode: 1,
+	})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	if err := scanner.AddOptions(WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+
+	var exitErr *ZmapExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatal("Expected that returned error is a ZmapExitError")
+	}
+	if !errors.Is(err, ErrGatewayMACNotFound) {
+		t.Error("Expected that returned error wraps ErrGatewayMACNotFound")
+	}
+	assert.Len(t, exitErr.Fatals, 1)
+	assert.NotEmpty(t, exitErr.Stderr)
+	assert.Contains(t, exitErr.Error(), "could not detect GW MAC address")
+}
@@ -28,36 +28,37 @@ func main() {
 		log.Fatalf("unable to add options to the async scanner: %v", err)
 	}
 
-	if err := scanner.RunAsync(); err != nil {
+	job, err := scanner.RunAsync()
+	if err != nil {
 		log.Fatalf("unable to run async scan: %v", err)
 	}
 
 	// Block main until the scan has completed
-	if err := scanner.Wait(); err != nil {
-		panic(err)
+	// Use job.Done() in a select to do something else while waiting, and job.Cancel() to stop the scan.
+	result, err := job.Wait()
+	if result == nil {
+		log.Fatalf("unable to run async scan: %v", err)
 	}
 
 	// it's always good to check for fatals.
-	fatals := scanner.GetFatalMessages()
-	for _, fatal := range fatals {
+	for _, fatal := range result.Fatals {
 		log.Printf("[FATAL]: %s", fatal.Message)
 	}
-	if len(fatals) > 0 {
+	if err != nil {
 		// So zmap did not work as expected and waiting for results would be pointless.
+		log.Printf("scan %s: %v", job.Status(), err)
 		os.Exit(1)
 	}
 
-	infos := scanner.GetInfoMessages()
-	for _, info := range infos {
+	for _, info := range result.Infos {
 		fmt.Println(strings.Repeat("-", 10))
 		fmt.Printf("Log Type: %s\n", info.LogType)
 		fmt.Printf("Message: %s\n", info.Message)
 	}
 
-	results := scanner.GetResults()
-	for _, result := range results {
+	for _, row := range result.Results {
 		fmt.Println(strings.Repeat("-", 10))
-		for key, value := range result {
+		for key, value := range row {
 			fmt.Printf("%s: %s\n", key, value)
 		}
 	}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
 	"strings"
 	"time"
 
@@ -34,18 +34,17 @@ func main() {
 		log.Fatalf("unable to add options: %v", err)
 	}
 
-	results, _, _, _, infos, fatals, err := scanner.RunBlocking()
+	results, _, _, _, infos, _, err := scanner.RunBlocking()
 	if err != nil {
-		log.Fatalf("unable to run zmap scan: %v", err)
-	}
-
-	// it's always good to check for fatals.
-	if len(fatals) > 0 {
-		// So zmap did not work as expected and waiting for results would be pointless.
-		for _, fatal := range fatals {
-			log.Printf("[FATAL]: %s", fatal.Message)
+		// FATAL lines of zmap are returned as a ZmapExitError.
+		var exitErr *zmapgo.ZmapExitError
+		if errors.As(err, &exitErr) {
+			for _, fatal := range exitErr.Fatals {
+				log.Printf("[FATAL]: %s", fatal.Message)
+			}
+			log.Fatalf("zmap exited with code %d", exitErr.ExitCode)
 		}
-		os.Exit(1)
+		log.Fatalf("unable to run zmap scan: %v", err)
 	}
 
 	// Print Info Messages.
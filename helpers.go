@@ -1,14 +1,112 @@
 package zmapgo
 
 import (
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 )
 
-func multiPassChecker(args []string, checkArgument string) error {
-	for _, arg := range args {
-		if arg == checkArgument {
-			return fmt.Errorf("found already added %s argument. Zmap does not allow multiple %s value", checkArgument, checkArgument)
+func multiPassChecker(args arguments, checkArgument string) error {
+	if args.has(checkArgument) {
+		return fmt.Errorf("found already added %s argument. Zmap does not allow multiple %s value", checkArgument, checkArgument)
+	}
+	return nil
+}
+
+// isIPv6ProbeModule returns true if probeModule sends IPv6 packets.
+func isIPv6ProbeModule(probeModule string) bool {
+	switch probeModule {
+	case ProbeModuleIPv6TCPSynScan, ProbeModuleIPv6UDP, ProbeModuleIPv6UDPDNS, ProbeModuleICMPv6Echo:
+		return true
+	}
+	return false
+}
+
+// isIPv4Target returns true if value is an ipv4 address or ipv4 cidr notation.
+func isIPv4Target(value string) bool {
+	if ip := net.ParseIP(value); ip != nil {
+		return ip.To4() != nil
+	}
+	if ip, _, err := net.ParseCIDR(value); err == nil {
+		return ip.To4() != nil
+	}
+	return false
+}
+
+// ipv4ArgsPassed returns true if scanner has ipv4 targets, ipv4 source ip or an ipv4 probe module.
+func (s *scanner) ipv4ArgsPassed() bool {
+	if len(s.targets) > 0 {
+		return true
+	}
+	if probeModule, ok := s.args.value("--probe-module"); ok && !isIPv6ProbeModule(probeModule) {
+		return true
+	}
+	if s.args.has("--source-ip") {
+		return true
+	}
+	// Targets may also be passed with WithCustomArguments.
+	for _, argument := range s.args {
+		if argument.Flag == "" && isIPv4Target(argument.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ipv6ArgsPassed returns true if scanner has ipv6 targets, ipv6 target file, ipv6 source ip or an ipv6 probe module.
+func (s *scanner) ipv6ArgsPassed() bool {
+	if len(s.ipv6Targets) > 0 {
+		return true
+	}
+	if probeModule, ok := s.args.value("--probe-module"); ok && isIPv6ProbeModule(probeModule) {
+		return true
+	}
+	return s.args.has("--ipv6-target-file") || s.args.has("--ipv6-source-ip")
+}
+
+// validatePortOrRange checks that value is a port number or a port range. Ex: "80", "8000-8100"
+func validatePortOrRange(value string) error {
+	parsePort := func(port string) (int, error) {
+		portValue, err := strconv.Atoi(port)
+		if err != nil {
+			return 0, fmt.Errorf("given port value %s is not a numeric value", port)
 		}
+		if !(portValue >= 0 && portValue <= 65535) {
+			return 0, errors.New("port value must be between 0 and 65535")
+		}
+		return portValue, nil
+	}
+
+	if !strings.Contains(value, "-") {
+		_, err := parsePort(value)
+		return err
+	}
+
+	splitted := strings.Split(value, "-")
+	if len(splitted) != 2 {
+		return fmt.Errorf("wrong port range definition %s", value)
+	}
+	lower, err := parsePort(splitted[0])
+	if err != nil {
+		return err
+	}
+	greater, err := parsePort(splitted[1])
+	if err != nil {
+		return err
+	}
+	if lower > greater {
+		return errors.New("lower part cannot be greater than greater part in port range definition")
 	}
 	return nil
 }
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,14 +2,16 @@ package zmapgo
 
 import (
 	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
 )
 
 func TestMultiPassChecker_NormalBehavior_ReturnError(t *testing.T) {
 	t.Log("Testing multiPassChecker function under normal behavior")
 
-	args := []string{
+	args := parseArguments([]string{
 		"--argument-one",
-	}
+	})
 
 	err := multiPassChecker(args, "--argument-one")
 	t.Logf("Returned Error: %v", err)
@@ -21,9 +23,9 @@ func TestMultiPassChecker_NormalBehavior_ReturnError(t *testing.T) {
 func TestMultiPassChecker_NormalBehavior_NotReturnError(t *testing.T) {
 	t.Log("Testing multiPassChecker function under normal behavior")
 
-	args := []string{
+	args := parseArguments([]string{
 		"--argument-one",
-	}
+	})
 
 	err := multiPassChecker(args, "--argument-two")
 	t.Logf("Returned Error: %v", err)
@@ -31,3 +33,26 @@ func TestMultiPassChecker_NormalBehavior_NotReturnError(t *testing.T) {
 		t.Error("Expected that error is returned when checking argument not exists")
 	}
 }
+
+// getArgumentValue returns the value of the first checkArgument in command line arguments.
+func getArgumentValue(args []string, checkArgument string) (string, bool) {
+	return parseArguments(args).value(checkArgument)
+}
+
+// icmpOutputFields are the output fields of icmp_echoscan probe module for the fake binary.
+var icmpOutputFields = map[string][]zmaptest.OutputField{
+	"icmp_echoscan": {
+		{Name: "saddr", Type: "string", Explanation: "source IP address of response"},
+		{Name: "daddr", Type: "string", Explanation: "destination IP address of response"},
+		{Name: "type", Type: "int", Explanation: "icmp message type"},
+		{Name: "code", Type: "int", Explanation: "icmp message sub type code"},
+		{Name: "data", Type: "binary", Explanation: "ICMP payload"},
+		{Name: "success", Type: "bool", Explanation: "is response considered success"},
+	},
+}
+
+// fakeZmapPath returns the path of a fake zmap binary with the default config of zmaptest.
+// It is used by the tests that only check options, so that they don't need zmap to be installed.
+func fakeZmapPath(t *testing.T) string {
+	return zmaptest.New(t, zmaptest.Config{}).Path
+}
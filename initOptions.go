@@ -3,12 +3,12 @@ package zmapgo
 import (
 	"context"
 	"errors"
-	"os"
-	"os/exec"
-	"strings"
+	"time"
 )
 
 // WithContext adds a context to a scanner, to make it cancellable and able to use timeout.
+// It bounds every call of the scanner. Use the ...Context variants, like RunBlockingContext and
+// ListOutputFieldsContext, to pass a different context to each call.
 func WithContext(ctx context.Context) InitOption {
 	return func(s *scanner) error {
 		// check ctx already exists
@@ -21,6 +21,8 @@ func WithContext(ctx context.Context) InitOption {
 }
 
 // WithBinaryPath sets the zmap binary path for a scanner
+// The binary is checked while the scanner is created. If a custom runner is passed with WithRunner,
+// the path is not checked on the local host, since zmap may be run somewhere else.
 func WithBinaryPath(binaryPath string) InitOption {
 	return func(s *scanner) error {
 		// check binary path already created
@@ -28,20 +30,66 @@ func WithBinaryPath(binaryPath string) InitOption {
 			return errors.New("binary path is already passed")
 		}
 
-		// check binary path exists
-		if _, err := os.Stat(binaryPath); errors.Is(err, os.ErrNotExist) {
-			return errors.New("given binary path does not exists")
+		s.binaryPath = binaryPath
+		s.binaryPathPassed = true
+		return nil
+	}
+}
+
+// WithRunner sets the runner that starts zmap processes for a scanner.
+// Binary path defaults to "zmap" if a runner is passed without WithBinaryPath.
+// Temporary files are not used with a custom runner. See NewPrefixRunner.
+func WithRunner(runner Runner) InitOption {
+	return func(s *scanner) error {
+		// check runner already created
+		if s.runner != nil {
+			return errors.New("runner is already passed")
+		}
+		if runner == nil {
+			return errors.New("runner cannot be nil")
+		}
+
+		s.runner = runner
+		return nil
+	}
+}
+
+// defaultGracePeriod is used if WithGracePeriod is not passed.
+const defaultGracePeriod = 5 * time.Second
+
+// WithGracePeriod sets the time to wait for zmap to exit after SIGINT when the context is done.
+// Zmap is killed if it does not exit in time. Zero grace period kills zmap immediately.
+// Default is 5 seconds.
+func WithGracePeriod(gracePeriod time.Duration) InitOption {
+	return func(s *scanner) error {
+		// check grace period already passed
+		if s.gracePeriodPassed {
+			return errors.New("grace period is already passed")
+		}
+		if gracePeriod < 0 {
+			return errors.New("grace period cannot be negative")
 		}
 
-		// check real zmap binary
-		out, _ := exec.Command(binaryPath, "--version").Output()
+		s.gracePeriod = gracePeriod
+		s.gracePeriodPassed = true
+		return nil
+	}
+}
 
-		trimed := strings.Trim(string(out), "\n")
-		if !strings.Contains(trimed, "zmap") {
-			return errors.New("given binary is not real zmap binary")
+// WithDiscoveryCache sets the cache of zmap discovery commands for a scanner.
+// Scanners that run zmap on the local host share a default cache. It is useful to share a cache between
+// scanners with a custom runner that run the same binary. See DiscoveryCache.
+func WithDiscoveryCache(cache *DiscoveryCache) InitOption {
+	return func(s *scanner) error {
+		// check discovery cache already passed
+		if s.discoveryCache != nil {
+			return errors.New("discovery cache is already passed")
+		}
+		if cache == nil {
+			return errors.New("discovery cache cannot be nil")
 		}
 
-		s.binaryPath = binaryPath
+		s.discoveryCache = cache
 		return nil
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWithContext_MultiplePassing(t *testing.T) {
@@ -26,14 +28,10 @@ func TestWithContext_MultiplePassing(t *testing.T) {
 
 func TestWithBinaryPath_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithBinaryPath function with multiple passing")
-	out, err := exec.Command("which", "zmap").Output()
-	if err != nil {
-		t.Errorf("Error while finding zmap binary: %v", err)
-	}
-	zmapBinary := strings.Trim(string(out), "\n")
-	t.Logf("Found zmap binary path: %s", zmapBinary)
+	zmapBinary := fakeZmapPath(t)
+	t.Logf("Created fake zmap binary path: %s", zmapBinary)
 
-	_, err = NewBlockingScanner(
+	_, err := NewBlockingScanner(
 		WithBinaryPath(zmapBinary),
 		WithBinaryPath(zmapBinary),
 	)
@@ -74,18 +72,64 @@ func TestWithBinaryPath_WrongBinary(t *testing.T) {
 
 func TestWithBinaryPath_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithBinaryPath function under normal behavior")
-	// find zmap binary
-	out, err := exec.Command("which", "zmap").Output()
+	zmapBinary := fakeZmapPath(t)
+	t.Logf("Created fake zmap binary path: %s", zmapBinary)
+
+	_, err := NewBlockingScanner(WithBinaryPath(zmapBinary))
+
+	t.Logf("Returned Error: %v", err)
 	if err != nil {
-		t.Errorf("Error while finding zmap binary: %v", err)
+		t.Error("Expected that error is not returned when passed zmap binary path")
 	}
-	zmapBinary := strings.Trim(string(out), "\n")
-	t.Logf("Found zmap binary path: %s", zmapBinary)
+}
 
-	_, err = NewBlockingScanner(WithBinaryPath(zmapBinary))
+func TestWithRunner_MultiplePassing(t *testing.T) {
+	t.Log("Testing WithRunner function with multiple passing")
+	_, err := NewBlockingScanner(
+		WithRunner(NewExecRunner()),
+		WithRunner(NewExecRunner()),
+	)
 
 	t.Logf("Returned Error: %v", err)
-	if err != nil {
-		t.Error("Expected that error is not returned when passed real zmap binary path")
+	if err == nil {
+		t.Error("Expected that error is returned when passed WithRunner more than one")
+	}
+}
+
+func TestWithRunner_NilRunner(t *testing.T) {
+	t.Log("Testing WithRunner function with nil runner")
+	_, err := NewBlockingScanner(WithRunner(nil))
+
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when passed nil runner")
+	}
+}
+
+func TestWithGracePeriod(t *testing.T) {
+	t.Log("Testing WithGracePeriod function")
+	tests := []struct {
+		testDesc        string
+		initOptions     []InitOption
+		isErrorExpected bool
+	}{
+		{
+			testDesc:        "With Negative Grace Period",
+			initOptions:     []InitOption{WithGracePeriod(-time.Second)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Multiple Passing",
+			initOptions:     []InitOption{WithGracePeriod(time.Second), WithGracePeriod(time.Second)},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			_, err := NewBlockingScanner(test.initOptions...)
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+		})
 	}
 }
@@ -0,0 +1,199 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ScanJob is a scan started by RunAsync. It is safe to use from multiple goroutines.
+type ScanJob struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mutex     sync.Mutex
+	status    JobStatus
+	cancelled bool
+	result    *ScanResult
+	err       error
+}
+
+func newScanJob(cancel context.CancelFunc) *ScanJob {
+	return &ScanJob{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		status: JobStatusPending,
+	}
+}
+
+// run runs the scan and records its result. Done is closed after the result is recorded.
+func (j *ScanJob) run(scan func() (*ScanResult, error)) {
+	j.mutex.Lock()
+	j.status = JobStatusRunning
+	j.mutex.Unlock()
+
+	result, err := scan()
+
+	j.mutex.Lock()
+	j.result = result
+	j.err = err
+	switch {
+	case err == nil:
+		j.status = JobStatusSucceeded
+	case j.cancelled || errors.Is(err, ErrScanTimeout):
+		j.status = JobStatusCancelled
+	default:
+		j.status = JobStatusFailed
+	}
+	j.mutex.Unlock()
+
+	j.cancel()
+	close(j.done)
+}
+
+// Done returns a channel that is closed when the scan is finished.
+func (j *ScanJob) Done() <-chan struct{} {
+	return j.done
+}
+
+// Status returns the current status of the scan.
+func (j *ScanJob) Status() JobStatus {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.status
+}
+
+// Cancel stops the scan. Zmap is stopped as it is done when the context is done, see WithGracePeriod.
+// The job is finished with JobStatusCancelled. Cancel does not wait for the scan to stop.
+func (j *ScanJob) Cancel() {
+	j.mutex.Lock()
+	if j.status == JobStatusPending || j.status == JobStatusRunning {
+		j.cancelled = true
+	}
+	j.mutex.Unlock()
+	j.cancel()
+}
+
+// Result returns the result and the error of the scan without blocking.
+// ErrJobNotDone is returned if the scan is not finished. ScanResult is nil only if zmap could not be started.
+func (j *ScanJob) Result() (*ScanResult, error) {
+	select {
+	case <-j.done:
+	default:
+		return nil, ErrJobNotDone
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	return j.result, j.err
+}
+
+// Wait blocks until the scan is finished and returns its result. See Result.
+func (j *ScanJob) Wait() (*ScanResult, error) {
+	<-j.done
+	return j.Result()
+}
+
+// RunAsync starts the scan in background and returns a ScanJob to follow it.
+// Options are validated before the scan is started, see Validate.
+// Every call starts a new scan with its own result, so several scans can run from one scanner at the same time.
+func (s *scanner) RunAsync() (*ScanJob, error) {
+	return s.RunAsyncContext(s.ctx)
+}
+
+// RunAsyncContext is RunAsync with a context for this scan.
+// The scan is stopped when ctx or the context of the scanner is done, or the job is cancelled.
+func (s *scanner) RunAsyncContext(ctx context.Context) (*ScanJob, error) {
+	if ctx == nil {
+		ctx = s.ctx
+	}
+	if err := s.ValidateContext(ctx); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	job := newScanJob(cancel)
+
+	s.lastJobMutex.Lock()
+	s.lastJob = job
+	s.lastJobMutex.Unlock()
+
+	go job.run(func() (*ScanResult, error) {
+		return s.Run(ctx)
+	})
+	return job, nil
+}
+
+// lastJobResult returns the result of the last job started by RunAsync.
+// Empty result is returned if there is no job or it is not finished.
+func (s *scanner) lastJobResult() *ScanResult {
+	s.lastJobMutex.Lock()
+	job := s.lastJob
+	s.lastJobMutex.Unlock()
+
+	if job == nil {
+		return &ScanResult{}
+	}
+	result, err := job.Result()
+	if result == nil || errors.Is(err, ErrJobNotDone) {
+		return &ScanResult{}
+	}
+	return result
+}
+
+// Wait blocks until the last job started by RunAsync is finished and returns its error.
+//
+// Deprecated: Use the ScanJob returned by RunAsync. Wait only follows the last started scan.
+func (s *scanner) Wait() error {
+	s.lastJobMutex.Lock()
+	job := s.lastJob
+	s.lastJobMutex.Unlock()
+
+	if job == nil {
+		return nil
+	}
+	_, err := job.Wait()
+	return err
+}
+
+// GetTraceMessages returns the trace logs of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetTraceMessages() []LogLine {
+	return s.lastJobResult().Traces
+}
+
+// GetDebugMessages returns the debug logs of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetDebugMessages() []LogLine {
+	return s.lastJobResult().Debugs
+}
+
+// GetWarningMessages returns the warning logs of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetWarningMessages() []LogLine {
+	return s.lastJobResult().Warnings
+}
+
+// GetInfoMessages returns the info logs of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetInfoMessages() []LogLine {
+	return s.lastJobResult().Infos
+}
+
+// GetFatalMessages returns the fatal logs of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetFatalMessages() []LogLine {
+	return s.lastJobResult().Fatals
+}
+
+// GetResults returns the results of the last job started by RunAsync.
+//
+// Deprecated: Use ScanJob.Result.
+func (s *scanner) GetResults() []map[string]interface{} {
+	return s.lastJobResult().Results
+}
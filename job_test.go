@@ -0,0 +1,133 @@
+package zmapgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAsyncScannerWithFake(t *testing.T, cfg zmaptest.Config, initOptions ...InitOption) AsyncScanner {
+	binary := zmaptest.New(t, cfg)
+	scanner, err := NewAsyncScanner(append([]InitOption{WithBinaryPath(binary.Path)}, initOptions...)...)
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.1"), WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+	return scanner
+}
+
+func TestRunAsync_ConcurrentJobs(t *testing.T) {
+	t.Log("Testing RunAsync function with several jobs from one scanner")
+	scanner := newAsyncScannerWithFake(t, zmaptest.Config{
+		Results: zmaptest.CSV([]string{"saddr"}, []string{"1.1.1.1"}),
+		Delay:   300 * time.Millisecond,
+	})
+
+	var jobs []*ScanJob
+	for i := 0; i < 3; i++ {
+		job, err := scanner.RunAsync()
+		if err != nil {
+			t.Fatalf("Expected that error is not returned while starting job: %v", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	_, err := jobs[0].Result()
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, ErrJobNotDone) {
+		t.Error("Expected that ErrJobNotDone is returned before the job is finished")
+	}
+	assert.Contains(t, []JobStatus{JobStatusPending, JobStatusRunning}, jobs[0].Status())
+
+	for _, job := range jobs {
+		select {
+		case <-job.Done():
+		case <-time.After(10 * time.Second):
+			t.Fatal("Expected that job is finished")
+		}
+
+		result, err := job.Result()
+		t.Logf("Returned Error: %v", err)
+		if err != nil {
+			t.Error("Expected that error is not returned")
+		}
+		assert.Equal(t, JobStatusSucceeded, job.Status())
+		if assert.NotNil(t, result) {
+			assert.Len(t, result.Results, 1, "Expected that every job has its own results")
+		}
+	}
+}
+
+func TestScanJob_Cancel(t *testing.T) {
+	t.Log("Testing Cancel function of ScanJob")
+	scanner := newAsyncScannerWithFake(t, zmaptest.Config{Delay: time.Minute}, WithGracePeriod(time.Second))
+
+	job, err := scanner.RunAsync()
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while starting job: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	job.Cancel()
+
+	select {
+	case <-job.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected that job is finished after Cancel")
+	}
+
+	result, err := job.Result()
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, ErrScanTimeout) {
+		t.Error("Expected that ErrScanTimeout is returned")
+	}
+	assert.NotNil(t, result)
+	assert.Equal(t, JobStatusCancelled, job.Status())
+}
+
+func TestScanJob_Failed(t *testing.T) {
+	t.Log("Testing ScanJob status when zmap fails")
+	scanner := newAsyncScannerWithFake(t, zmaptest.Config{
+		Logs:     []string{zmaptest.LogLine("FATAL", "recv: could not open device eth9")},
+		ExitCode: 1,
+	})
+
+	job, err := scanner.RunAsync()
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while starting job: %v", err)
+	}
+
+	_, err = job.Wait()
+	t.Logf("Returned Error: %v", err)
+	var exitErr *ZmapExitError
+	if !errors.As(err, &exitErr) {
+		t.Error("Expected that ZmapExitError is returned")
+	}
+	assert.Equal(t, JobStatusFailed, job.Status())
+
+	// Deprecated getters follow the last job.
+	assert.Equal(t, err, scanner.Wait())
+	assert.Len(t, scanner.GetFatalMessages(), 1)
+}
+
+func TestRunAsync_Validate(t *testing.T) {
+	t.Log("Testing RunAsync function returns validation errors before starting the job")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewAsyncScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	job, err := scanner.RunAsync()
+	t.Logf("Returned Error: %v", err)
+	var violations ValidationErrors
+	if !errors.As(err, &violations) {
+		t.Error("Expected that ValidationErrors is returned")
+	}
+	assert.Nil(t, job)
+	assert.NoError(t, scanner.Wait(), "Expected that Wait returns immediately without jobs")
+}
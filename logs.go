@@ -0,0 +1,278 @@
+package zmapgo
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tailInterval is the interval of looking for new lines in log files.
+var tailInterval = 100 * time.Millisecond
+
+// LogHandler is called for every log line of zmap while scan is running.
+type LogHandler func(logLine LogLine)
+
+type logSubscriber struct {
+	handler LogHandler
+	levels  map[LogLevel]bool
+}
+
+func (l *logSubscriber) accepts(level LogLevel) bool {
+	return l.levels == nil || l.levels[level]
+}
+
+// logSubscribers holds the log handlers of a scanner.
+type logSubscribers struct {
+	mutex       sync.Mutex
+	lastID      int
+	subscribers map[int]*logSubscriber
+}
+
+func (l *logSubscribers) subscribe(handler LogHandler, levels ...LogLevel) func() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	subscriber := &logSubscriber{handler: handler}
+	if len(levels) > 0 {
+		subscriber.levels = map[LogLevel]bool{}
+		for _, level := range levels {
+			subscriber.levels[level] = true
+		}
+	}
+
+	if l.subscribers == nil {
+		l.subscribers = map[int]*logSubscriber{}
+	}
+	l.lastID++
+	id := l.lastID
+	l.subscribers[id] = subscriber
+
+	return func() {
+		l.mutex.Lock()
+		defer l.mutex.Unlock()
+		delete(l.subscribers, id)
+	}
+}
+
+func (l *logSubscribers) publish(logLine LogLine) {
+	l.mutex.Lock()
+	var handlers []LogHandler
+	for _, subscriber := range l.subscribers {
+		if subscriber.accepts(LogLevel(logLine.LogType)) {
+			handlers = append(handlers, subscriber.handler)
+		}
+	}
+	l.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(logLine)
+	}
+}
+
+// OnLog registers handler to receive log lines while zmap is running.
+// If levels are given, only log lines of those levels are passed to handler.
+// Handler is called from the goroutine that reads the logs, so it should return quickly.
+// The returned function unregisters the handler.
+func (s *scanner) OnLog(handler LogHandler, levels ...LogLevel) (unsubscribe func()) {
+	return s.logSubscribers.subscribe(handler, levels...)
+}
+
+// logCollector groups parsed log lines by their level and passes them to subscribers.
+type logCollector struct {
+	traces   []LogLine
+	debugs   []LogLine
+	warnings []LogLine
+	infos    []LogLine
+	fatals   []LogLine
+
+	// err is the first error while parsing log lines.
+	err error
+
+	subscribers *logSubscribers
+	// onFatal is called when FATAL log line is parsed.
+	onFatal func()
+}
+
+// addLine parses line and collects it if it is a log line.
+func (c *logCollector) addLine(line string) {
+	var level LogLevel
+	switch {
+	case strings.Contains(line, "[TRACE]"):
+		level = LogLevelTrace
+	case strings.Contains(line, "[DEBUG]"):
+		level = LogLevelDebug
+	case strings.Contains(line, "[WARN]"):
+		level = LogLevelWarn
+	case strings.Contains(line, "[INFO]"):
+		level = LogLevelInfo
+	case strings.Contains(line, "[FATAL]"):
+		level = LogLevelFatal
+	default:
+		// Not a log line. Ex: status updates
+		return
+	}
+
+	logLine, err := parseLogLine(line)
+	if err != nil {
+		if c.err == nil {
+			c.err = err
+		}
+		return
+	}
+
+	switch level {
+	case LogLevelTrace:
+		c.traces = append(c.traces, logLine)
+	case LogLevelDebug:
+		c.debugs = append(c.debugs, logLine)
+	case LogLevelWarn:
+		c.warnings = append(c.warnings, logLine)
+	case LogLevelInfo:
+		c.infos = append(c.infos, logLine)
+	case LogLevelFatal:
+		c.fatals = append(c.fatals, logLine)
+	}
+
+	if c.subscribers != nil {
+		c.subscribers.publish(logLine)
+	}
+
+	if level == LogLevelFatal && c.onFatal != nil {
+		c.onFatal()
+	}
+}
+
+// readLines passes every line in ioReader to handleLine until EOF.
+func readLines(ioReader io.Reader, handleLine func(line string)) {
+	reader := bufio.NewReader(ioReader)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			handleLine(strings.TrimRight(line, "\r\n"))
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// tailFile passes every line written to the file to handleLine until stop is closed.
+// resolvePath returns the path of the file, or empty string if file is not created yet.
+// Lines written before stop is closed are always passed.
+func tailFile(resolvePath func() string, stop <-chan struct{}, handleLine func(line string)) {
+	var (
+		file    *os.File
+		reader  *bufio.Reader
+		offset  int64
+		partial string
+	)
+
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(tailInterval)
+	defer ticker.Stop()
+
+	for {
+		stopped := false
+		select {
+		case <-stop:
+			stopped = true
+		case <-ticker.C:
+		}
+
+		if file == nil {
+			if path := resolvePath(); path != "" {
+				if f, err := os.Open(path); err == nil {
+					file = f
+					reader = bufio.NewReader(file)
+				}
+			}
+		}
+
+		if file != nil {
+			// Start from beginning if file is truncated.
+			if fileInfo, err := file.Stat(); err == nil && fileInfo.Size() < offset {
+				if _, err := file.Seek(0, io.SeekStart); err == nil {
+					offset = 0
+					partial = ""
+					reader.Reset(file)
+				}
+			}
+
+			for {
+				chunk, err := reader.ReadString('\n')
+				offset += int64(len(chunk))
+				if err != nil {
+					// Line is not completely written yet.
+					partial += chunk
+					break
+				}
+				handleLine(strings.TrimRight(partial+chunk, "\r\n"))
+				partial = ""
+			}
+		}
+
+		if stopped {
+			if partial != "" {
+				handleLine(strings.TrimRight(partial, "\r\n"))
+			}
+			return
+		}
+	}
+}
+
+// logDirectoryResolver returns a function that finds the log file created by zmap in logDirectoryPath.
+// Files that are already in the directory are skipped unless they are modified.
+func logDirectoryResolver(logDirectoryPath string) func() string {
+	existingFiles := map[string]time.Time{}
+	if fileInfos, err := ioutil.ReadDir(logDirectoryPath); err == nil {
+		for _, fileInfo := range fileInfos {
+			existingFiles[fileInfo.Name()] = fileInfo.ModTime()
+		}
+	}
+
+	// zmap names log files with the time it is started.
+	timeLayout := "zmap-2006-01-02T150405-0700.log"
+
+	return func() string {
+		fileInfos, err := ioutil.ReadDir(logDirectoryPath)
+		if err != nil {
+			return ""
+		}
+
+		var (
+			latestFile string
+			latestTime time.Time
+		)
+		for _, fileInfo := range fileInfos {
+			if fileInfo.IsDir() {
+				continue
+			}
+			fileTime, err := time.Parse(timeLayout, fileInfo.Name())
+			if err != nil {
+				continue
+			}
+			if modTime, ok := existingFiles[fileInfo.Name()]; ok && modTime.Equal(fileInfo.ModTime()) {
+				continue
+			}
+			if latestFile == "" || fileTime.After(latestTime) {
+				latestFile = fileInfo.Name()
+				latestTime = fileTime
+			}
+		}
+
+		if latestFile == "" {
+			return ""
+		}
+		return filepath.Join(logDirectoryPath, latestFile)
+	}
+}
@@ -0,0 +1,125 @@
+package zmapgo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLogs = `Dec 10 12:00:00.001 [INFO] zmap: output module: csv
+Dec 10 12:00:00.002 [DEBUG] zmap: no interface provided. will use default interface (eth0).
+ 0:00 0%; send: 0 0 p/s (0 p/s avg); recv: 0 0 p/s (0 p/s avg); drops: 0 p/s (0 p/s avg); hitrate: 0.00%
+Dec 10 12:00:00.003 [TRACE] recv: thread started
+Dec 10 12:00:00.004 [WARN] blacklist: ZMap is currently using the default blacklist located at /etc/zmap/blacklist.conf
+Dec 10 12:00:00.005 [FATAL] send: could not send any probes
+`
+
+func TestLogCollector_NormalBehavior(t *testing.T) {
+	t.Log("Testing logCollector under normal behavior")
+	fatalCalled := false
+	collector := &logCollector{
+		onFatal: func() { fatalCalled = true },
+	}
+
+	readLines(strings.NewReader(testLogs), collector.addLine)
+
+	assert.NoError(t, collector.err)
+	assert.Len(t, collector.traces, 1)
+	assert.Len(t, collector.debugs, 1)
+	assert.Len(t, collector.warnings, 1)
+	assert.Len(t, collector.infos, 1)
+	assert.Len(t, collector.fatals, 1)
+	assert.Equal(t, "send: could not send any probes", collector.fatals[0].Message)
+	assert.True(t, fatalCalled, "Expected that onFatal is called when fatal log line is parsed")
+}
+
+func TestLogCollector_WrongLogLine(t *testing.T) {
+	t.Log("Testing logCollector with log line that has wrong time format")
+	collector := &logCollector{}
+
+	collector.addLine("wrong time [INFO] message")
+
+	t.Logf("Collected Error: %v", collector.err)
+	if collector.err == nil {
+		t.Error("Expected that error is collected when log line cannot be parsed")
+	}
+}
+
+func TestOnLog_LevelFilter(t *testing.T) {
+	t.Log("Testing OnLog function with level filter")
+	s := &scanner{}
+
+	var all, warnAndFatal []LogLine
+	s.OnLog(func(logLine LogLine) {
+		all = append(all, logLine)
+	})
+	unsubscribe := s.OnLog(func(logLine LogLine) {
+		warnAndFatal = append(warnAndFatal, logLine)
+	}, LogLevelWarn, LogLevelFatal)
+
+	collector := &logCollector{subscribers: &s.logSubscribers}
+	readLines(strings.NewReader(testLogs), collector.addLine)
+
+	assert.Len(t, all, 5)
+	if assert.Len(t, warnAndFatal, 2) {
+		assert.Equal(t, string(LogLevelWarn), warnAndFatal[0].LogType)
+		assert.Equal(t, string(LogLevelFatal), warnAndFatal[1].LogType)
+	}
+
+	// Unsubscribed handler should not receive new lines.
+	unsubscribe()
+	collector.addLine("Dec 10 12:00:00.006 [WARN] zmap: another warning")
+	assert.Len(t, all, 6)
+	assert.Len(t, warnAndFatal, 2)
+}
+
+func TestTailFile_NormalBehavior(t *testing.T) {
+	t.Log("Testing tailFile function under normal behavior")
+	logFilePath := filepath.Join(t.TempDir(), "zmap.log")
+
+	var lines []string
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		tailFile(func() string { return logFilePath }, stop, func(line string) {
+			lines = append(lines, line)
+		})
+	}()
+
+	logFile, err := os.Create(logFilePath)
+	if err != nil {
+		t.Fatalf("Cannot create log file: %v", err)
+	}
+	defer logFile.Close()
+
+	_, _ = logFile.WriteString("first line\nsecond ")
+	_, _ = logFile.WriteString("line\nlast line without new line")
+
+	close(stop)
+	<-done
+
+	assert.Equal(t, []string{"first line", "second line", "last line without new line"}, lines)
+}
+
+func TestLogDirectoryResolver_SkipExistingFiles(t *testing.T) {
+	t.Log("Testing logDirectoryResolver function with existing log files")
+	logDirectoryPath := t.TempDir()
+
+	oldLogFile := filepath.Join(logDirectoryPath, "zmap-2021-12-10T120000+0000.log")
+	if err := os.WriteFile(oldLogFile, []byte("old"), 0644); err != nil {
+		t.Fatalf("Cannot create log file: %v", err)
+	}
+
+	resolve := logDirectoryResolver(logDirectoryPath)
+	assert.Equal(t, "", resolve(), "Expected that existing log files are skipped")
+
+	newLogFile := filepath.Join(logDirectoryPath, "zmap-2021-12-10T130000+0000.log")
+	if err := os.WriteFile(newLogFile, []byte("new"), 0644); err != nil {
+		t.Fatalf("Cannot create log file: %v", err)
+	}
+	assert.Equal(t, newLogFile, resolve())
+}
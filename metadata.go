@@ -0,0 +1,208 @@
+package zmapgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ScanConfig is the effective configuration of a scan reported in scan metadata.
+type ScanConfig struct {
+	TargetPort        int
+	SourcePortFirst   int
+	SourcePortLast    int
+	MaxTargets        uint64
+	MaxRuntime        int
+	MaxResults        int
+	Interface         string
+	Rate              int
+	Bandwidth         uint64
+	CooldownSecs      int
+	Senders           int
+	Seed              uint64
+	SeedProvided      bool
+	Generator         uint64
+	ShardNum          int
+	TotalShards       int
+	MinHitrate        float64
+	MaxSendtoFailures int
+	PacketStreams     int
+	ProbeModule       string
+	OutputModule      string
+	SourceMAC         string
+	GatewayMAC        string
+	Dryrun            bool
+}
+
+// ScanMetadata is the scan metadata that zmap writes to the file passed with --metadata-file.
+type ScanMetadata struct {
+	// ScanConfig is embedded, since zmap writes the configuration and the statistics into the same JSON object.
+	ScanConfig
+
+	PacketsSent     uint64
+	PacketsReceived uint64
+	PacketsDropped  uint64
+	SendtoFailures  uint64
+	SuccessTotal    uint64
+	SuccessUnique   uint64
+	FailureTotal    uint64
+	Hitrate         float64
+
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Notes is the value passed with --notes.
+	Notes string
+	// UserMetadata is the JSON passed with --user-metadata.
+	UserMetadata json.RawMessage
+
+	// Raw holds every key in the metadata, including the ones that have no dedicated field.
+	Raw map[string]json.RawMessage
+}
+
+// ParseScanMetadata parses the JSON that zmap writes to the metadata file.
+// Values are parsed leniently, because field types differ between zmap versions.
+func ParseScanMetadata(ioReader io.Reader) (*ScanMetadata, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(ioReader).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	values := metadataValues(raw)
+	metadata := &ScanMetadata{
+		ScanConfig: ScanConfig{
+			TargetPort:        int(values.int("target_port")),
+			SourcePortFirst:   int(values.int("source_port_first")),
+			SourcePortLast:    int(values.int("source_port_last")),
+			MaxTargets:        uint64(values.int("max_targets")),
+			MaxRuntime:        int(values.int("max_runtime")),
+			MaxResults:        int(values.int("max_results")),
+			Interface:         values.string("iface"),
+			Rate:              int(values.int("rate")),
+			Bandwidth:         uint64(values.int("bandwidth")),
+			CooldownSecs:      int(values.int("cooldown_secs")),
+			Senders:           int(values.int("senders")),
+			Seed:              uint64(values.int("seed")),
+			SeedProvided:      values.bool("seed_provided"),
+			Generator:         uint64(values.int("generator")),
+			ShardNum:          int(values.int("shard_num")),
+			TotalShards:       int(values.int("total_shards")),
+			MinHitrate:        values.float("min_hitrate"),
+			MaxSendtoFailures: int(values.int("max_sendto_failures")),
+			PacketStreams:     int(values.int("packet_streams")),
+			ProbeModule:       values.string("probe_module"),
+			OutputModule:      values.string("output_module"),
+			SourceMAC:         values.string("source_mac"),
+			GatewayMAC:        values.string("gateway_mac"),
+			Dryrun:            values.bool("dryrun"),
+		},
+		PacketsSent:     uint64(values.int("total_sent")),
+		PacketsReceived: uint64(values.int("pcap_recv")),
+		PacketsDropped:  uint64(values.int("pcap_drop")),
+		SendtoFailures:  uint64(values.int("sendto_failures")),
+		SuccessTotal:    uint64(values.int("success_total")),
+		SuccessUnique:   uint64(values.int("success_unique")),
+		FailureTotal:    uint64(values.int("failure_total")),
+		Hitrate:         values.float("hitrate"),
+		StartTime:       values.time("start_time"),
+		EndTime:         values.time("end_time"),
+		Notes:           values.string("notes"),
+		UserMetadata:    raw["user-metadata"],
+		Raw:             raw,
+	}
+
+	return metadata, nil
+}
+
+// metadataValues converts raw JSON values into go types.
+// Missing keys and values that cannot be converted result in zero value.
+type metadataValues map[string]json.RawMessage
+
+func (m metadataValues) value(key string) interface{} {
+	rawValue, ok := m[key]
+	if !ok {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(rawValue))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil
+	}
+	return value
+}
+
+func (m metadataValues) string(key string) string {
+	switch v := m.value(key).(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	return ""
+}
+
+func (m metadataValues) int(key string) int64 {
+	switch v := m.value(key).(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return int64(u)
+		}
+		if f, err := v.Float64(); err == nil {
+			return int64(f)
+		}
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	case bool:
+		if v {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (m metadataValues) float(key string) float64 {
+	switch v := m.value(key).(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
+}
+
+func (m metadataValues) bool(key string) bool {
+	switch v := m.value(key).(type) {
+	case bool:
+		return v
+	case json.Number:
+		return v.String() != "0"
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
+
+func (m metadataValues) time(key string) time.Time {
+	value := m.string(key)
+	layouts := []string{
+		"2006-01-02T15:04:05-0700",
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05",
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
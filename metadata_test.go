@@ -0,0 +1,80 @@
+package zmapgo
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMetadata = `{
+	"target_port": 80,
+	"source_port_first": 32768,
+	"source_port_last": 61000,
+	"max_targets": 256,
+	"iface": "eth0",
+	"rate": 10000,
+	"bandwidth": 0,
+	"cooldown_secs": 8,
+	"senders": 1,
+	"seed": 18446744073709551615,
+	"seed_provided": 0,
+	"hitrate": 1.5625,
+	"probe_module": "tcp_synscan",
+	"output_module": "csv",
+	"dryrun": false,
+	"total_sent": 256,
+	"pcap_recv": 10,
+	"pcap_drop": 0,
+	"sendto_failures": 3,
+	"max_sendto_failures": -1,
+	"success_total": 4,
+	"success_unique": 4,
+	"failure_total": 6,
+	"start_time": "2021-12-10T12:00:00-0500",
+	"end_time": "2021-12-10T12:00:10-0500",
+	"notes": "weekly scan",
+	"user-metadata": {"team": "research"},
+	"unknown_key": "value"
+}`
+
+func TestParseScanMetadata_NormalBehavior(t *testing.T) {
+	t.Log("Testing ParseScanMetadata function under normal behavior")
+	metadata, err := ParseScanMetadata(strings.NewReader(testMetadata))
+	t.Logf("Returned Error: %v", err)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, 80, metadata.TargetPort)
+	assert.Equal(t, 32768, metadata.SourcePortFirst)
+	assert.Equal(t, 61000, metadata.SourcePortLast)
+	assert.Equal(t, uint64(256), metadata.MaxTargets)
+	assert.Equal(t, "eth0", metadata.Interface)
+	assert.Equal(t, 10000, metadata.Rate)
+	assert.Equal(t, uint64(18446744073709551615), metadata.Seed)
+	assert.False(t, metadata.SeedProvided)
+	assert.Equal(t, "tcp_synscan", metadata.ProbeModule)
+	assert.Equal(t, uint64(256), metadata.PacketsSent)
+	assert.Equal(t, uint64(10), metadata.PacketsReceived)
+	assert.Equal(t, uint64(3), metadata.SendtoFailures)
+	assert.Equal(t, -1, metadata.MaxSendtoFailures)
+	assert.Equal(t, uint64(4), metadata.SuccessTotal)
+	assert.Equal(t, uint64(6), metadata.FailureTotal)
+	assert.Equal(t, 1.5625, metadata.Hitrate)
+	assert.Equal(t, time.Date(2021, 12, 10, 17, 0, 0, 0, time.UTC), metadata.StartTime.UTC())
+	assert.Equal(t, 10*time.Second, metadata.EndTime.Sub(metadata.StartTime))
+	assert.Equal(t, "weekly scan", metadata.Notes)
+	assert.JSONEq(t, `{"team": "research"}`, string(metadata.UserMetadata))
+	assert.Contains(t, metadata.Raw, "unknown_key")
+}
+
+func TestParseScanMetadata_NotJSON(t *testing.T) {
+	t.Log("Testing ParseScanMetadata function with non-json content")
+	_, err := ParseScanMetadata(strings.NewReader("not json"))
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when metadata is not a json")
+	}
+}
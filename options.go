@@ -20,7 +20,7 @@ import (
 // of the official zmap release.
 func WithCustomArguments(args ...string) Option {
 	return func(s *scanner) error {
-		s.args = append(s.args, args...)
+		s.args = append(s.args, parseArguments(args)...)
 		return nil
 	}
 }
@@ -30,53 +30,76 @@ func WithCustomArguments(args ...string) Option {
 ////////////////////////////////////////
 
 // WithTargets sets the target informations to give to the zmap binary.
-// Targets can be ip address or cidr notation
+// Targets can be ip address or cidr notation.
+// IPv4 targets are passed as arguments. If there are more than 256 targets, they are written to a
+// temporary whitelist file instead, so that large lists don't hit ARG_MAX or show up in `ps` output.
+// IPv6 targets require FeatureIPv6 and can only be ip addresses. They are written to a temporary
+// ipv6 target file while running. IPv4 and ipv6 targets cannot be scanned together.
 func WithTargets(targets ...string) Option {
 	return func(s *scanner) error {
-		var realTargets []string
+		var ipv4Targets, ipv6Targets []string
 		for _, target := range targets {
-			isValidIPAddress := false
-			isValidCIDR := false
-
-			// Check target is valid ipv4 address
-			ipAddress := net.ParseIP(target)
-			if ipAddress.To4() != nil {
-				isValidIPAddress = true
+			// Check target is valid ip address
+			if ipAddress := net.ParseIP(target); ipAddress != nil {
+				if ipAddress.To4() != nil {
+					ipv4Targets = append(ipv4Targets, ipAddress.To4().String())
+				} else {
+					ipv6Targets = append(ipv6Targets, ipAddress.String())
+				}
+				continue
 			}
 
 			// Check target is valid ipv4 cidr notation
 			ip, n, err := net.ParseCIDR(target)
 			if err == nil && ip.To4() != nil {
-				isValidCIDR = true
+				ipv4Targets = append(ipv4Targets, n.String())
+				continue
 			}
-
-			if isValidIPAddress {
-				realTargets = append(realTargets, ipAddress.To4().String())
+			if err == nil {
+				return fmt.Errorf("given value of %s is an ipv6 cidr notation. Zmap accepts only ipv6 addresses as ipv6 targets", target)
 			}
 
-			if isValidCIDR {
-				realTargets = append(realTargets, n.String())
-			}
+			return fmt.Errorf("given value of %s is not a valid ip address or ipv4 cidr notation", target)
+		}
 
-			if !isValidCIDR && !isValidIPAddress {
-				return fmt.Errorf("given value of %s is not a valid ipv4 ipaddress or ipv4 cidr notation", target)
+		if len(ipv4Targets) > 0 && len(ipv6Targets) > 0 {
+			return errors.New("ipv4 and ipv6 targets cannot be used together")
+		}
+
+		if len(ipv4Targets) > 0 && s.ipv6ArgsPassed() {
+			return errors.New("ipv4 targets cannot be used with ipv6 targets, ipv6 source ip or ipv6 probe modules")
+		}
+
+		if len(ipv6Targets) > 0 {
+			if err := s.requireFeature(FeatureIPv6); err != nil {
+				return err
+			}
+			if s.ipv4ArgsPassed() {
+				return errors.New("ipv6 targets cannot be used with ipv4 targets, ipv4 source ip or ipv4 probe modules")
+			}
+			if s.args.has("--ipv6-target-file") {
+				return errors.New("ipv6 targets cannot be used with ipv6 target file")
 			}
 		}
 
-		s.args = append(s.args, realTargets...)
+		s.targets = append(s.targets, ipv4Targets...)
+		s.ipv6Targets = append(s.ipv6Targets, ipv6Targets...)
 		return nil
 	}
 }
 
 // WithTargetPort sets the target port to give to the zmap binary.
 // This is required option. And should be used for ones.
-// Zmap does not support multiple ports
+// Zmap 2.x does not support multiple ports. Use WithTargetPorts for newer releases.
 func WithTargetPort(targetPort string) Option {
 	return func(s *scanner) error {
 		// check multiple usage
 		if err := multiPassChecker(s.args, "--target-port"); err != nil {
 			return err
 		}
+		if err := multiPassChecker(s.args, "--target-ports"); err != nil {
+			return errors.New("target port cannot be used with target ports")
+		}
 
 		// check valid port number
 		portValue, err := strconv.Atoi(targetPort)
@@ -88,8 +111,43 @@ func WithTargetPort(targetPort string) Option {
 			return errors.New("target port value must be between 0 and 65535")
 		}
 
-		s.args = append(s.args, "--target-port")
-		s.args = append(s.args, targetPort)
+		s.args.add("--target-port", targetPort)
+		return nil
+	}
+}
+
+// WithTargetPorts sets the target ports to give to the zmap binary.
+// Ports can be single ports, ranges or comma separated lists of them. Ex: WithTargetPorts("80,443", "8000-8100")
+// It requires FeatureMultiPort and cannot be used with WithTargetPort.
+// Results are tagged with "dport" output field, so that rows of different ports can be told apart.
+func WithTargetPorts(ports ...string) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--target-ports"); err != nil {
+			return err
+		}
+		if err := multiPassChecker(s.args, "--target-port"); err != nil {
+			return errors.New("target ports cannot be used with target port")
+		}
+
+		var realPorts []string
+		for _, port := range ports {
+			for _, part := range strings.Split(port, ",") {
+				part = strings.TrimSpace(part)
+				if err := validatePortOrRange(part); err != nil {
+					return err
+				}
+				realPorts = append(realPorts, part)
+			}
+		}
+		if len(realPorts) == 0 {
+			return errors.New("at least one target port is required")
+		}
+
+		if err := s.requireFeature(FeatureMultiPort); err != nil {
+			return err
+		}
+
+		s.args.add("--target-ports", strings.Join(realPorts, ","))
 		return nil
 	}
 }
@@ -117,8 +175,7 @@ func WithOutputFile(outputFile string) Option {
 			}
 		}
 
-		s.args = append(s.args, "--output-file")
-		s.args = append(s.args, outputFile)
+		s.args.add("--output-file", outputFile)
 		return nil
 	}
 }
@@ -130,13 +187,15 @@ func WithBlacklistFile(blacklistFile string) Option {
 		if err := multiPassChecker(s.args, "--blacklist-file"); err != nil {
 			return err
 		}
+		if s.blocklist != nil {
+			return errors.New("blacklist file cannot be used with blocklist")
+		}
 
 		if _, err := os.Stat(blacklistFile); errors.Is(err, os.ErrNotExist) {
 			return errors.New("blacklist file is not exists")
 		}
 
-		s.args = append(s.args, "--blacklist-file")
-		s.args = append(s.args, blacklistFile)
+		s.args.add("--blacklist-file", blacklistFile)
 		return nil
 	}
 }
@@ -147,13 +206,139 @@ func WithWhitelistFile(whitelistFile string) Option {
 		if err := multiPassChecker(s.args, "--whitelist-file"); err != nil {
 			return err
 		}
+		if s.allowlist != nil {
+			return errors.New("whitelist file cannot be used with allowlist")
+		}
 
 		if _, err := os.Stat(whitelistFile); errors.Is(err, os.ErrNotExist) {
 			return errors.New("whitelist file is not exists")
 		}
 
-		s.args = append(s.args, "--whitelist-file", whitelistFile)
-		s.args = append(s.args, whitelistFile)
+		s.args.add("--whitelist-file", whitelistFile)
+		return nil
+	}
+}
+
+// WithBlocklist sets the networks that zmap should not scan.
+// The blocklist is written to a temporary file in zmap format while running, and given as blacklist file.
+// Changes to the blocklist after this option is added are used by the next runs.
+// It cannot be used with WithBlacklistFile.
+func WithBlocklist(blocklist *Blocklist) Option {
+	return func(s *scanner) error {
+		if blocklist == nil {
+			return errors.New("blocklist cannot be nil")
+		}
+		if s.blocklist != nil {
+			return errors.New("blocklist is already passed")
+		}
+		if err := multiPassChecker(s.args, "--blacklist-file"); err != nil {
+			return errors.New("blocklist cannot be used with blacklist file")
+		}
+
+		s.blocklist = blocklist
+		return nil
+	}
+}
+
+// WithAllowlist sets the only networks that zmap is allowed to scan.
+// The allowlist is written to a temporary file in zmap format while running, and given as whitelist file.
+// Changes to the allowlist after this option is added are used by the next runs.
+// It cannot be used with WithWhitelistFile.
+func WithAllowlist(allowlist *Blocklist) Option {
+	return func(s *scanner) error {
+		if allowlist == nil {
+			return errors.New("allowlist cannot be nil")
+		}
+		if s.allowlist != nil {
+			return errors.New("allowlist is already passed")
+		}
+		if err := multiPassChecker(s.args, "--whitelist-file"); err != nil {
+			return errors.New("allowlist cannot be used with whitelist file")
+		}
+
+		s.allowlist = allowlist
+		return nil
+	}
+}
+
+// WithListOfIPsFile sets the list of ips file to give to the zmap binary.
+// File should contain one ip address per line. Unlike whitelist file, cidr notations are not accepted,
+// but zmap reads it faster for long lists of individual addresses. It requires FeatureListOfIPs.
+func WithListOfIPsFile(listOfIPsFile string) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--list-of-ips-file"); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(listOfIPsFile); errors.Is(err, os.ErrNotExist) {
+			return errors.New("list of ips file is not exists")
+		}
+
+		if err := s.requireFeature(FeatureListOfIPs); err != nil {
+			return err
+		}
+
+		s.args.add("--list-of-ips-file", listOfIPsFile)
+		return nil
+	}
+}
+
+////////////////////////////////////////
+////// IPv6 Options Section
+////////////////////////////////////////
+
+// WithIPv6TargetFile sets the ipv6 target file to give to the zmap binary.
+// File should contain one ipv6 address per line. It requires FeatureIPv6.
+// It should be used with WithIPv6SourceIP and an ipv6 probe module. Ex: ProbeModuleIPv6TCPSynScan
+func WithIPv6TargetFile(ipv6TargetFile string) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--ipv6-target-file"); err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(ipv6TargetFile); errors.Is(err, os.ErrNotExist) {
+			return errors.New("ipv6 target file is not exists")
+		}
+
+		if err := s.requireFeature(FeatureIPv6); err != nil {
+			return err
+		}
+
+		if s.ipv4ArgsPassed() {
+			return errors.New("ipv6 target file cannot be used with ipv4 targets, ipv4 source ip or ipv4 probe modules")
+		}
+
+		if len(s.ipv6Targets) > 0 {
+			return errors.New("ipv6 target file cannot be used with ipv6 targets")
+		}
+
+		s.args.add("--ipv6-target-file", ipv6TargetFile)
+		return nil
+	}
+}
+
+// WithIPv6SourceIP sets the ipv6 source address to give to the zmap binary.
+// It requires FeatureIPv6.
+func WithIPv6SourceIP(sourceIP string) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--ipv6-source-ip"); err != nil {
+			return err
+		}
+
+		ipAddress := net.ParseIP(sourceIP)
+		if ipAddress == nil || ipAddress.To4() != nil {
+			return errors.New("given value is not valid ipv6 address")
+		}
+
+		if err := s.requireFeature(FeatureIPv6); err != nil {
+			return err
+		}
+
+		if s.ipv4ArgsPassed() {
+			return errors.New("ipv6 source ip cannot be used with ipv4 targets, ipv4 source ip or ipv4 probe modules")
+		}
+
+		s.args.add("--ipv6-source-ip", ipAddress.String())
 		return nil
 	}
 }
@@ -174,8 +359,7 @@ func WithRate(rate string) Option {
 			return errors.New("given rate value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--rate")
-		s.args = append(s.args, rate)
+		s.args.add("--rate", rate)
 		return nil
 	}
 }
@@ -219,8 +403,7 @@ func WithBandwidth(bandwidth string, unit BandwidthUnit) Option {
 			realValue += string(unit)
 		}
 
-		s.args = append(s.args, "--bandwidth")
-		s.args = append(s.args, realValue)
+		s.args.add("--bandwidth", realValue)
 		return nil
 	}
 }
@@ -240,8 +423,7 @@ func WithMaxTargets(maxTarget string, isPercentage bool) Option {
 			maxTarget += "%"
 		}
 
-		s.args = append(s.args, "--max-targets")
-		s.args = append(s.args, maxTarget)
+		s.args.add("--max-targets", maxTarget)
 		return nil
 	}
 }
@@ -257,8 +439,7 @@ func WithMaxRuntime(maxRuntime string) Option {
 			return errors.New("given max runtime value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--max-runtime")
-		s.args = append(s.args, maxRuntime)
+		s.args.add("--max-runtime", maxRuntime)
 		return nil
 	}
 }
@@ -274,8 +455,7 @@ func WithMaxResults(maxResults string) Option {
 			return errors.New("given max results value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--max-results")
-		s.args = append(s.args, maxResults)
+		s.args.add("--max-results", maxResults)
 		return nil
 	}
 }
@@ -291,8 +471,7 @@ func WithNumberOfProbesPerIP(numberOfProbes string) Option {
 			return errors.New("given number of probes value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--probes")
-		s.args = append(s.args, numberOfProbes)
+		s.args.add("--probes", numberOfProbes)
 		return nil
 	}
 }
@@ -309,8 +488,7 @@ func WithCooldownTime(cooldown string) Option {
 			return errors.New("given cooldown value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--cooldown-time")
-		s.args = append(s.args, cooldown)
+		s.args.add("--cooldown-time", cooldown)
 		return nil
 	}
 }
@@ -327,8 +505,7 @@ func WithSeed(seed string) Option {
 			return errors.New("given seed value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--seed")
-		s.args = append(s.args, seed)
+		s.args.add("--seed", seed)
 		return nil
 	}
 }
@@ -345,21 +522,20 @@ func WithMaxRetries(maxRetries string) Option {
 			return errors.New("given max retries value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--retries")
-		s.args = append(s.args, maxRetries)
+		s.args.add("--retries", maxRetries)
 		return nil
 	}
 }
 
 // WithDryrun sets the dryrun to give to zmap binary.
-// Don't actually send packets
+// Don't actually send packets. The packets that would be sent are returned in ScanResult.DryRunPackets.
 func WithDryrun() Option {
 	return func(s *scanner) error {
 		if err := multiPassChecker(s.args, "--dryrun"); err != nil {
 			return err
 		}
 
-		s.args = append(s.args, "--dryrun")
+		s.args.addFlag("--dryrun")
 		return nil
 	}
 }
@@ -376,8 +552,7 @@ func WithTotalShards(shards string) Option {
 			return errors.New("given shards value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--shards")
-		s.args = append(s.args, shards)
+		s.args.add("--shards", shards)
 		return nil
 	}
 }
@@ -394,8 +569,7 @@ func WithShardID(shardID string) Option {
 			return errors.New("given shardID value is not a numeric value")
 		}
 
-		s.args = append(s.args, "--shard")
-		s.args = append(s.args, shardID)
+		s.args.add("--shard", shardID)
 		return nil
 	}
 }
@@ -465,8 +639,7 @@ func WithSourcePort(sourcePort string) Option {
 			realValue = fmt.Sprintf("%d", portValue)
 		}
 
-		s.args = append(s.args, "--source-port")
-		s.args = append(s.args, realValue)
+		s.args.add("--source-port", realValue)
 		return nil
 	}
 }
@@ -480,6 +653,10 @@ func WithSourceIP(sourceIP string) Option {
 			return err
 		}
 
+		if s.ipv6ArgsPassed() {
+			return errors.New("ipv4 source ip cannot be used with ipv6 targets, ipv6 source ip or ipv6 probe modules. Use WithIPv6SourceIP instead")
+		}
+
 		var realValue string
 
 		if strings.Contains(sourceIP, "-") {
@@ -516,8 +693,7 @@ func WithSourceIP(sourceIP string) Option {
 			realValue = ipAddress.To4().String()
 		}
 
-		s.args = append(s.args, "--source-ip")
-		s.args = append(s.args, realValue)
+		s.args.add("--source-ip", realValue)
 		return nil
 	}
 }
@@ -534,8 +710,7 @@ func WithGatewayMAC(gatewayMAC string) Option {
 			return errors.New("given value is not valid mac address")
 		}
 
-		s.args = append(s.args, "--gateway-mac")
-		s.args = append(s.args, gatewayMAC)
+		s.args.add("--gateway-mac", gatewayMAC)
 		return nil
 	}
 }
@@ -552,8 +727,7 @@ func WithSourceMAC(sourceMAC string) Option {
 			return errors.New("given value is not valid mac address")
 		}
 
-		s.args = append(s.args, "--source-mac")
-		s.args = append(s.args, sourceMAC)
+		s.args.add("--source-mac", sourceMAC)
 		return nil
 	}
 }
@@ -583,8 +757,7 @@ func WithInterface(ifa string) Option {
 			return errors.New("given interface name is not available on the system")
 		}
 
-		s.args = append(s.args, "--interface")
-		s.args = append(s.args, ifa)
+		s.args.add("--interface", ifa)
 		return nil
 	}
 }
@@ -597,7 +770,24 @@ func WithVPN() Option {
 			return err
 		}
 
-		s.args = append(s.args, "--vpn")
+		s.args.addFlag("--vpn")
+		return nil
+	}
+}
+
+// WithIPLayer sets the iplayer flag to give to zmap binary.
+// Sends IP packets instead of Ethernet (for VPNs). It requires FeatureIPLayer.
+func WithIPLayer() Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--iplayer"); err != nil {
+			return err
+		}
+
+		if err := s.requireFeature(FeatureIPLayer); err != nil {
+			return err
+		}
+
+		s.args.addFlag("--iplayer")
 		return nil
 	}
 }
@@ -630,8 +820,20 @@ func WithProbeModule(probeModule string) Option {
 			return errors.New("given probe module is not in available probe modules")
 		}
 
-		s.args = append(s.args, "--probe-module")
-		s.args = append(s.args, probeModule)
+		// check address family of probe module matches with targets and source ip
+		if isIPv6ProbeModule(probeModule) {
+			if err := s.requireFeature(FeatureIPv6); err != nil {
+				return err
+			}
+		}
+		if isIPv6ProbeModule(probeModule) && s.ipv4ArgsPassed() {
+			return errors.New("ipv6 probe module cannot be used with ipv4 targets or ipv4 source ip")
+		}
+		if !isIPv6ProbeModule(probeModule) && s.ipv6ArgsPassed() {
+			return errors.New("ipv4 probe module cannot be used with ipv6 targets or ipv6 source ip")
+		}
+
+		s.args.add("--probe-module", probeModule)
 		return nil
 	}
 }
@@ -644,8 +846,7 @@ func WithProbeArgs(probeArgs string) Option {
 			return err
 		}
 
-		s.args = append(s.args, "--probe-args")
-		s.args = append(s.args, probeArgs)
+		s.args.add("--probe-args", probeArgs)
 		return nil
 	}
 }
@@ -656,6 +857,8 @@ func WithProbeArgs(probeArgs string) Option {
 
 // WithOutputFields sets the output fields to give to zmap binary.
 // Fields that should be output in result set
+// Fields are checked against the probe module passed with WithProbeModule, or against the fields of every
+// probe module if it is not passed yet.
 func WithOutputFields(fields []string) Option {
 	return func(s *scanner) error {
 		if err := multiPassChecker(s.args, "--output-fields"); err != nil {
@@ -668,22 +871,27 @@ func WithOutputFields(fields []string) Option {
 		}
 
 		for _, field := range fields {
-			found := false
-			for _, aField := range availableFields {
-				if field == aField.Name {
-					found = true
-					break
-				}
+			if outputFieldExists(availableFields, field) {
+				continue
+			}
+			if _, ok := s.effectiveArguments().value("--probe-module"); ok {
+				return fmt.Errorf("given field %s is not in available fields of probe module", field)
+			}
+
+			// Probe module may be passed after output fields. Fields of every probe module are accepted
+			// until then, and the fields are checked against the probe module by Validate.
+			availableFields, err = s.allOutputFields()
+			if err != nil {
+				return err
 			}
-			if !found {
+			if !outputFieldExists(availableFields, field) {
 				return fmt.Errorf("given field %s is not in available fields", field)
 			}
 		}
 
 		realValue := strings.Join(fields, ",")
 
-		s.args = append(s.args, "--output-fields")
-		s.args = append(s.args, realValue)
+		s.args.add("--output-fields", realValue)
 		return nil
 	}
 }
@@ -713,8 +921,7 @@ func WithOutputModule(outputModule string) Option {
 			return errors.New("given output module is not in available output modules")
 		}
 
-		s.args = append(s.args, "--output-module")
-		s.args = append(s.args, outputModule)
+		s.args.add("--output-module", outputModule)
 		return nil
 	}
 }
@@ -727,8 +934,7 @@ func WithOutputArgs(outputArgs string) Option {
 			return err
 		}
 
-		s.args = append(s.args, "--output-args")
-		s.args = append(s.args, outputArgs)
+		s.args.add("--output-args", outputArgs)
 		return nil
 	}
 }
@@ -743,8 +949,51 @@ func WithOutputFilter(outputFilter string) Option {
 			return err
 		}
 
-		s.args = append(s.args, "--output-filter")
-		s.args = append(s.args, outputFilter)
+		s.args.add("--output-filter", outputFilter)
+		return nil
+	}
+}
+
+// WithDedupMethod sets the dedup method to give to zmap binary.
+// Specifies how response packets are de-duplicated. It requires FeatureDedup.
+func WithDedupMethod(dedupMethod DedupMethod) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--dedup-method"); err != nil {
+			return err
+		}
+
+		switch dedupMethod {
+		case DedupMethodDefault, DedupMethodNone, DedupMethodFull, DedupMethodWindow:
+		default:
+			return errors.New("given dedup method is not valid")
+		}
+
+		if err := s.requireFeature(FeatureDedup); err != nil {
+			return err
+		}
+
+		s.args.add("--dedup-method", string(dedupMethod))
+		return nil
+	}
+}
+
+// WithDedupWindowSize sets the dedup window size to give to zmap binary.
+// Window size for how many recent responses to keep track of. It requires FeatureDedup.
+func WithDedupWindowSize(windowSize string) Option {
+	return func(s *scanner) error {
+		if err := multiPassChecker(s.args, "--dedup-window-size"); err != nil {
+			return err
+		}
+
+		if value, err := strconv.Atoi(windowSize); err != nil || value <= 0 {
+			return errors.New("given dedup window size is not a positive numeric value")
+		}
+
+		if err := s.requireFeature(FeatureDedup); err != nil {
+			return err
+		}
+
+		s.args.add("--dedup-window-size", windowSize)
 		return nil
 	}
 }
@@ -781,8 +1030,7 @@ func WithVerbosity(verbosityLevel VerbosityLevel) Option {
 			return errors.New("given verbosity level is not in available verbosity levels")
 		}
 
-		s.args = append(s.args, "--verbosity")
-		s.args = append(s.args, string(verbosityLevel))
+		s.args.add("--verbosity", string(verbosityLevel))
 		return nil
 	}
 }
@@ -796,14 +1044,7 @@ func WithLogFile(logFile string) Option {
 		}
 
 		// check --log-directory already passed. If passed, not permit.
-		isLogDirectoryPassed := false
-		for _, arg := range s.args {
-			if arg == "--log-directory" {
-				isLogDirectoryPassed = true
-			}
-		}
-
-		if isLogDirectoryPassed {
+		if s.args.has("--log-directory") {
 			return errors.New("log-file and log-directory cannot specified simultaneously")
 		}
 		// check passed path is a directory. If directory, not permit.
@@ -819,8 +1060,7 @@ func WithLogFile(logFile string) Option {
 			}
 		}
 
-		s.args = append(s.args, "--log-file")
-		s.args = append(s.args, logFile)
+		s.args.add("--log-file", logFile)
 		return nil
 	}
 }
@@ -834,14 +1074,7 @@ func WithLogDirectory(logDirectory string) Option {
 		}
 
 		// check --log-file already passed. If passed, not permit
-		isLogFilePassed := false
-		for _, arg := range s.args {
-			if arg == "--log-file" {
-				isLogFilePassed = true
-			}
-		}
-
-		if isLogFilePassed {
+		if s.args.has("--log-file") {
 			return errors.New("log-file and log-directory cannot specified simultaneously")
 		}
 		// check passed directory already exists. If not exists, not permit.
@@ -854,8 +1087,7 @@ func WithLogDirectory(logDirectory string) Option {
 			return errors.New("given log-directory path is not a directory")
 		}
 
-		s.args = append(s.args, "--log-directory")
-		s.args = append(s.args, logDirectory)
+		s.args.add("--log-directory", logDirectory)
 		return nil
 	}
 }
@@ -881,8 +1113,7 @@ func WithMetadataFile(metadataFile string) Option {
 			}
 		}
 
-		s.args = append(s.args, "--metadata-file")
-		s.args = append(s.args, metadataFile)
+		s.args.add("--metadata-file", metadataFile)
 		return nil
 	}
 }
@@ -907,8 +1138,7 @@ func WithStatusUpdatesFile(statusUpdateFile string) Option {
 				return errors.New("given status updates file's parent directory is not exists")
 			}
 		}
-		s.args = append(s.args, "--status-updates-file")
-		s.args = append(s.args, statusUpdateFile)
+		s.args.add("--status-updates-file", statusUpdateFile)
 		return nil
 	}
 }
@@ -921,7 +1151,7 @@ func WithQuiet() Option {
 			return err
 		}
 
-		s.args = append(s.args, "--quiet")
+		s.args.addFlag("--quiet")
 		return nil
 	}
 }
@@ -934,7 +1164,7 @@ func WithDisableSyslog() Option {
 			return err
 		}
 
-		s.args = append(s.args, "--disable-syslog")
+		s.args.addFlag("--disable-syslog")
 		return nil
 	}
 }
@@ -947,8 +1177,7 @@ func WithNotes(notes string) Option {
 			return err
 		}
 
-		s.args = append(s.args, "--notes")
-		s.args = append(s.args, notes)
+		s.args.add("--notes", notes)
 		return nil
 	}
 }
@@ -961,8 +1190,7 @@ func WithUserMetadata(userMetadata string) Option {
 			return err
 		}
 
-		s.args = append(s.args, "--user-metadata")
-		s.args = append(s.args, userMetadata)
+		s.args.add("--user-metadata", userMetadata)
 		return nil
 	}
 }
@@ -974,6 +1202,7 @@ func WithUserMetadata(userMetadata string) Option {
 // WithConfigFile sets the config file to give to zmap binary.
 // Read a configuration file, which can specify any of these options
 // (default=`/usr/local/etc/zmap/zmap.conf')
+// Use LoadZmapConfig instead to inspect or override the options of the file in code.
 func WithConfigFile(configFile string) Option {
 	return func(s *scanner) error {
 		if err := multiPassChecker(s.args, "--config"); err != nil {
@@ -991,8 +1220,7 @@ func WithConfigFile(configFile string) Option {
 			return errors.New("config file path is a directory")
 		}
 
-		s.args = append(s.args, "--config")
-		s.args = append(s.args, configFile)
+		s.args.add("--config", configFile)
 		return nil
 	}
 }
@@ -1009,8 +1237,7 @@ func WithMaxSendtoFailures(maxSendtoFailures string) Option {
 			return errors.New("max send failures is not a valid numeric value")
 		}
 
-		s.args = append(s.args, "--max-sendto-failures")
-		s.args = append(s.args, maxSendtoFailures)
+		s.args.add("--max-sendto-failures", maxSendtoFailures)
 		return nil
 	}
 }
@@ -1026,8 +1253,7 @@ func WithMinHitrate(minHitrate string) Option {
 		if _, err := decimal.NewFromString(minHitrate); err != nil {
 			return errors.New("min hitrate is not a valid decimal number")
 		}
-		s.args = append(s.args, "--min-hitrate")
-		s.args = append(s.args, minHitrate)
+		s.args.add("--min-hitrate", minHitrate)
 		return nil
 	}
 }
@@ -1044,8 +1270,7 @@ func WithSenderThreads(senderThreads string) Option {
 			return errors.New("sender threads is not a valid numeric value")
 		}
 
-		s.args = append(s.args, "--sender-threads")
-		s.args = append(s.args, senderThreads)
+		s.args.add("--sender-threads", senderThreads)
 		return nil
 	}
 }
@@ -1072,8 +1297,7 @@ func WithCores(cores []string) Option {
 			}
 		}
 
-		s.args = append(s.args, "--cores")
-		s.args = append(s.args, strings.Join(cores, ","))
+		s.args.add("--cores", strings.Join(cores, ","))
 		return nil
 	}
 }
@@ -1086,7 +1310,7 @@ func WithIgnoreInvalidHosts() Option {
 			return err
 		}
 
-		s.args = append(s.args, "--ignore-invalid-hosts")
+		s.args.addFlag("--ignore-invalid-hosts")
 		return nil
 	}
 }
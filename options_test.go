@@ -1,15 +1,21 @@
 package zmapgo
 
 import (
+	"context"
+	"io/ioutil"
 	"net"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWithCustomArguments_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithCustomArguments function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -23,7 +29,7 @@ func TestWithCustomArguments_NormalBehavior(t *testing.T) {
 
 func TestWithTargets_NotIPv4(t *testing.T) {
 	t.Log("Testing WithTargets function with wrong format ipv4 address")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -37,23 +43,60 @@ func TestWithTargets_NotIPv4(t *testing.T) {
 }
 
 func TestWithTargets_WithIPv6(t *testing.T) {
-	t.Log("Testing WithTargets function with ipv6 address")
-	scanner, err := NewBlockingScanner()
-	if err != nil {
-		t.Error("Cannot create zmapgo scanner to test")
-	}
-
-	err = scanner.AddOptions(WithTargets("FE80:CD00:0000:0CDE:1257:0000:211E:729C"))
-
-	t.Logf("Returned Error: %v", err)
-	if err == nil {
-		t.Error("Expected that error is returned when passed ipv6 address")
+	t.Log("Testing WithTargets function with ipv6 addresses")
+	tests := []struct {
+		testDesc        string
+		version         string
+		targets         []string
+		expectedTargets []string
+		isErrorExpected bool
+	}{
+		{
+			testDesc:        "With IPv6 Address",
+			version:         "4.0.0",
+			targets:         []string{"FE80:CD00:0000:0CDE:1257:0000:211E:729C", "2001:db8::1"},
+			expectedTargets: []string{"fe80:cd00:0:cde:1257:0:211e:729c", "2001:db8::1"},
+		},
+		{
+			testDesc:        "With IPv6 CIDR",
+			version:         "4.0.0",
+			targets:         []string{"2001:db8::/64"},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 And IPv6 Addresses",
+			version:         "4.0.0",
+			targets:         []string{"192.168.1.1", "2001:db8::1"},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Address On Zmap 3.0.0",
+			version:         "3.0.0",
+			targets:         []string{"2001:db8::1"},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{Version: test.version})
+			s, err := newScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = s.AddOptions(WithTargets(test.targets...))
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+			assert.Equal(t, test.expectedTargets, s.ipv6Targets)
+			assert.Empty(t, s.targets)
+		})
 	}
 }
 
 func TestWithTargets_WithWrongCIDR(t *testing.T) {
 	t.Log("Testing WithTargets function with wrong cidr notation")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -68,7 +111,7 @@ func TestWithTargets_WithWrongCIDR(t *testing.T) {
 
 func TestWithTargets_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithTargets function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -83,7 +126,7 @@ func TestWithTargets_NormalBehavior(t *testing.T) {
 
 func TestWithTargetPort_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithTargetPort function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -104,7 +147,7 @@ func TestWithTargetPort_MultiplePassing(t *testing.T) {
 
 func TestWithTargetPort_PortValueNotNumeric(t *testing.T) {
 	t.Log("Testing WithTargetPort function with non-numeric target port")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -119,7 +162,7 @@ func TestWithTargetPort_PortValueNotNumeric(t *testing.T) {
 
 func TestWithTargetPort_WrongPortValue(t *testing.T) {
 	t.Log("Testing WithTargetPort function with passing wrong target port")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -132,7 +175,7 @@ func TestWithTargetPort_WrongPortValue(t *testing.T) {
 		t.Error("Expected that error is returned when passed bigger than 65535 as target port")
 	}
 
-	scanner, err = NewBlockingScanner()
+	scanner, err = NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -149,7 +192,7 @@ func TestWithTargetPort_WrongPortValue(t *testing.T) {
 func TestWithTargetPort_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithTargetPort function under normal behavior")
 
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -164,7 +207,7 @@ func TestWithTargetPort_NormalBehavior(t *testing.T) {
 
 func TestWithOutputFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithOutputFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -185,7 +228,7 @@ func TestWithOutputFile_MultiplePassing(t *testing.T) {
 
 func TestWithOutputFile_WithStdoutValue(t *testing.T) {
 	t.Log("Testing WithOutputFile function with passing value of '-' to pass output to stdout")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -200,7 +243,7 @@ func TestWithOutputFile_WithStdoutValue(t *testing.T) {
 
 func TestWithOutputFile_ParentNotExists(t *testing.T) {
 	t.Log("Testing WithOutputFile function with passing parent directory does not exists")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -215,7 +258,7 @@ func TestWithOutputFile_ParentNotExists(t *testing.T) {
 
 func TestWithOutputFile_PathIsDirectory(t *testing.T) {
 	t.Log("Testing WithOutputFile function with passing directory path")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -230,7 +273,7 @@ func TestWithOutputFile_PathIsDirectory(t *testing.T) {
 
 func TestWithOutputFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithOutputFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -245,7 +288,7 @@ func TestWithOutputFile_NormalBehavior(t *testing.T) {
 
 func TestWithBlacklistFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithBlacklistFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -266,7 +309,7 @@ func TestWithBlacklistFile_MultiplePassing(t *testing.T) {
 
 func TestWithBlacklistFile_FilePathNotExists(t *testing.T) {
 	t.Log("Testing WithBlacklistFile function with passing not existing file path as value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -281,7 +324,7 @@ func TestWithBlacklistFile_FilePathNotExists(t *testing.T) {
 
 func TestWithBlacklistFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithBlacklistFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -296,7 +339,7 @@ func TestWithBlacklistFile_NormalBehavior(t *testing.T) {
 
 func TestWithWhitelistFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithWhitelistFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -317,7 +360,7 @@ func TestWithWhitelistFile_MultiplePassing(t *testing.T) {
 
 func TestWithWhitelistFile_FilePathNotExists(t *testing.T) {
 	t.Log("Testing WithWhitelistFile function with passing not existing file path as value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -332,7 +375,7 @@ func TestWithWhitelistFile_FilePathNotExists(t *testing.T) {
 
 func TestWithWhitelistFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithWhitelistFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -347,7 +390,7 @@ func TestWithWhitelistFile_NormalBehavior(t *testing.T) {
 
 func TestWithRate_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithRate function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -368,7 +411,7 @@ func TestWithRate_MultiplePassing(t *testing.T) {
 
 func TestWithRate_ValueNotNumeric(t *testing.T) {
 	t.Log("Testing WithRate function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -383,7 +426,7 @@ func TestWithRate_ValueNotNumeric(t *testing.T) {
 
 func TestWithRate_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithRate function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -398,7 +441,7 @@ func TestWithRate_NormalBehavior(t *testing.T) {
 
 func TestWithBandwidth_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithBandwidth function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -419,7 +462,7 @@ func TestWithBandwidth_MultiplePassing(t *testing.T) {
 
 func TestWithBandwidth_NonNumericBandwidth(t *testing.T) {
 	t.Log("Testing WithBandwidth function with non numeric bandwidth value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -434,7 +477,7 @@ func TestWithBandwidth_NonNumericBandwidth(t *testing.T) {
 
 func TestWithBandwidth_UnsupportedUnit(t *testing.T) {
 	t.Log("Testing WithBandwidth function with unsupported unit")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -450,7 +493,7 @@ func TestWithBandwidth_UnsupportedUnit(t *testing.T) {
 
 func TestWithBandwidth_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithBandwidth function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -465,7 +508,7 @@ func TestWithBandwidth_NormalBehavior(t *testing.T) {
 
 func TestWithMaxTargets_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMaxTargets function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -486,7 +529,7 @@ func TestWithMaxTargets_MultiplePassing(t *testing.T) {
 
 func TestWithMaxTargets_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithMaxTargets function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -501,7 +544,7 @@ func TestWithMaxTargets_NonNumericValue(t *testing.T) {
 
 func TestWithMaxTargets_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMaxTargets function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -516,7 +559,7 @@ func TestWithMaxTargets_NormalBehavior(t *testing.T) {
 
 func TestWithMaxRuntime_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMaxRuntime function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -537,7 +580,7 @@ func TestWithMaxRuntime_MultiplePassing(t *testing.T) {
 
 func TestWithMaxRuntime_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithMaxRuntime function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -552,7 +595,7 @@ func TestWithMaxRuntime_NonNumericValue(t *testing.T) {
 
 func TestWithMaxRuntime_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMaxRuntime function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -567,7 +610,7 @@ func TestWithMaxRuntime_NormalBehavior(t *testing.T) {
 
 func TestWithMaxResults_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMaxResults function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -588,7 +631,7 @@ func TestWithMaxResults_MultiplePassing(t *testing.T) {
 
 func TestWithMaxResults_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithMaxResults function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -603,7 +646,7 @@ func TestWithMaxResults_NonNumericValue(t *testing.T) {
 
 func TestWithMaxResults_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMaxResults function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -618,7 +661,7 @@ func TestWithMaxResults_NormalBehavior(t *testing.T) {
 
 func TestWithNumberOfProbesPerIP_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithNumberOfProbesPerIP function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -639,7 +682,7 @@ func TestWithNumberOfProbesPerIP_MultiplePassing(t *testing.T) {
 
 func TestWithNumberOfProbesPerIP_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithNumberOfProbesPerIP function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -654,7 +697,7 @@ func TestWithNumberOfProbesPerIP_NonNumericValue(t *testing.T) {
 
 func TestWithNumberOfProbesPerIP_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithNumberOfProbesPerIP function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -669,7 +712,7 @@ func TestWithNumberOfProbesPerIP_NormalBehavior(t *testing.T) {
 
 func TestWithCooldownTime_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithCooldownTime function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -690,7 +733,7 @@ func TestWithCooldownTime_MultiplePassing(t *testing.T) {
 
 func TestWithCooldownTime_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithCooldownTime function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -705,7 +748,7 @@ func TestWithCooldownTime_NonNumericValue(t *testing.T) {
 
 func TestWithCooldownTime_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithCooldownTime function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -720,7 +763,7 @@ func TestWithCooldownTime_NormalBehavior(t *testing.T) {
 
 func TestWithSeed_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithSeed function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -741,7 +784,7 @@ func TestWithSeed_MultiplePassing(t *testing.T) {
 
 func TestWithSeed_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithSeed function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -756,7 +799,7 @@ func TestWithSeed_NonNumericValue(t *testing.T) {
 
 func TestWithSeed_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSeed function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -771,7 +814,7 @@ func TestWithSeed_NormalBehavior(t *testing.T) {
 
 func TestWithMaxRetries_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMaxRetries function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -792,7 +835,7 @@ func TestWithMaxRetries_MultiplePassing(t *testing.T) {
 
 func TestWithMaxRetries_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithMaxRetries function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -807,7 +850,7 @@ func TestWithMaxRetries_NonNumericValue(t *testing.T) {
 
 func TestWithMaxRetries_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMaxRetries function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -822,7 +865,7 @@ func TestWithMaxRetries_NormalBehavior(t *testing.T) {
 
 func TestWithDryrun_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithDryrun function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -843,7 +886,7 @@ func TestWithDryrun_MultiplePassing(t *testing.T) {
 
 func TestWithDryrun_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithDryrun function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -858,7 +901,7 @@ func TestWithDryrun_NormalBehavior(t *testing.T) {
 
 func TestWithTotalShards_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithTotalShards function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -879,7 +922,7 @@ func TestWithTotalShards_MultiplePassing(t *testing.T) {
 
 func TestWithTotalShards_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithTotalShards function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -894,7 +937,7 @@ func TestWithTotalShards_NonNumericValue(t *testing.T) {
 
 func TestWithTotalShards_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithTotalShards function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -909,7 +952,7 @@ func TestWithTotalShards_NormalBehavior(t *testing.T) {
 
 func TestWithShardID_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithShardID function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -930,7 +973,7 @@ func TestWithShardID_MultiplePassing(t *testing.T) {
 
 func TestWithShardID_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithShardID function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -945,7 +988,7 @@ func TestWithShardID_NonNumericValue(t *testing.T) {
 
 func TestWithShardID_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithShardID function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -960,7 +1003,7 @@ func TestWithShardID_NormalBehavior(t *testing.T) {
 
 func TestWithSourcePort_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithSourcePort function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -981,7 +1024,7 @@ func TestWithSourcePort_MultiplePassing(t *testing.T) {
 
 func TestWithSourcePort_Range_NotValidRange(t *testing.T) {
 	t.Log("Testing WithSourcePort function with not valid range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -996,7 +1039,7 @@ func TestWithSourcePort_Range_NotValidRange(t *testing.T) {
 
 func TestWithSourcePort_Range_LowerNonNumeric(t *testing.T) {
 	t.Log("Testing WithSourcePort function with non numeric value in lower part of range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1011,7 +1054,7 @@ func TestWithSourcePort_Range_LowerNonNumeric(t *testing.T) {
 
 func TestWithSourcePort_Range_LowerNotValidPortNumber(t *testing.T) {
 	t.Log("Testing WithSourcePort function with wrong value in lower part of range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1026,7 +1069,7 @@ func TestWithSourcePort_Range_LowerNotValidPortNumber(t *testing.T) {
 
 func TestWithSourcePort_Range_GreaterNonNumeric(t *testing.T) {
 	t.Log("Testing WithSourcePort function with non numeric value in greater part of range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1041,7 +1084,7 @@ func TestWithSourcePort_Range_GreaterNonNumeric(t *testing.T) {
 
 func TestWithSourcePort_Range_GreaterNotValidPortNumber(t *testing.T) {
 	t.Log("Testing WithSourcePort function with wrong value in greater part of range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1056,7 +1099,7 @@ func TestWithSourcePort_Range_GreaterNotValidPortNumber(t *testing.T) {
 
 func TestWithSourcePort_Range_LowerAndGreaterEqual(t *testing.T) {
 	t.Log("Testing WithSourcePort function with greater and lower part equal in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1071,7 +1114,7 @@ func TestWithSourcePort_Range_LowerAndGreaterEqual(t *testing.T) {
 
 func TestWithSourcePort_Range_LowerGreaterThanGreater(t *testing.T) {
 	t.Log("Testing WithSourcePort function with lower value greater than greater part value in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1086,7 +1129,7 @@ func TestWithSourcePort_Range_LowerGreaterThanGreater(t *testing.T) {
 
 func TestWithSourcePort_Single_NonNumeric(t *testing.T) {
 	t.Log("Testing WithSourcePort function with non-numeric value in single port definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1101,7 +1144,7 @@ func TestWithSourcePort_Single_NonNumeric(t *testing.T) {
 
 func TestWithSourcePort_Single_NotValidPortNumber(t *testing.T) {
 	t.Log("Testing WithSourcePort function with wrong value in single port definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1116,7 +1159,7 @@ func TestWithSourcePort_Single_NotValidPortNumber(t *testing.T) {
 
 func TestWithSorucePort_Range_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSourcePort function under normal behavior in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1131,7 +1174,7 @@ func TestWithSorucePort_Range_NormalBehavior(t *testing.T) {
 
 func TestWithSorucePort_Single_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSourcePort function under normal behavior in single port definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1146,7 +1189,7 @@ func TestWithSorucePort_Single_NormalBehavior(t *testing.T) {
 
 func TestWithSourceIP_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithSourceIP function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1167,7 +1210,7 @@ func TestWithSourceIP_MultiplePassing(t *testing.T) {
 
 func TestWithSourceIP_Range_NotValidRange(t *testing.T) {
 	t.Log("Testing WithSourceIP function with not valid range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1182,7 +1225,7 @@ func TestWithSourceIP_Range_NotValidRange(t *testing.T) {
 
 func TestWithSourceIP_Range_NotValidLowerPart(t *testing.T) {
 	t.Log("Testing WithSourceIP function with non-valid lower part")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1197,7 +1240,7 @@ func TestWithSourceIP_Range_NotValidLowerPart(t *testing.T) {
 
 func TestWithSourceIP_Range_NotValidGreaterPart(t *testing.T) {
 	t.Log("Testing WithSourceIP function with non-valid greater part")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1212,7 +1255,7 @@ func TestWithSourceIP_Range_NotValidGreaterPart(t *testing.T) {
 
 func TestWithSourceIP_Range_NotValidLowerAndGreaterEqual(t *testing.T) {
 	t.Log("Testing WithSourceIP function with lower and greater equal in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1227,7 +1270,7 @@ func TestWithSourceIP_Range_NotValidLowerAndGreaterEqual(t *testing.T) {
 
 func TestWithSourceIP_Range_NotValidLowerGreaterThanGreater(t *testing.T) {
 	t.Log("Testing WithSourceIP function with lower part greater than greater part in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1242,7 +1285,7 @@ func TestWithSourceIP_Range_NotValidLowerGreaterThanGreater(t *testing.T) {
 
 func TestWithSourceIP_Single_NotValidIP(t *testing.T) {
 	t.Log("Testing WithSourceIP function with non-valid ip in single definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1257,7 +1300,7 @@ func TestWithSourceIP_Single_NotValidIP(t *testing.T) {
 
 func TestWithSourceIP_Range_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSourceIP function under normal behavior in range definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1272,7 +1315,7 @@ func TestWithSourceIP_Range_NormalBehavior(t *testing.T) {
 
 func TestWithSourceIP_Single_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSourceIP function under normal behavior in single definition")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1287,7 +1330,7 @@ func TestWithSourceIP_Single_NormalBehavior(t *testing.T) {
 
 func TestWithGatewayMAC_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithGatewayMAC function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1323,7 +1366,7 @@ func TestWithGatewayMAC_MultiplePassing(t *testing.T) {
 
 func TestWithGatewayMAC_NonValidMAC(t *testing.T) {
 	t.Log("Testing WithGatewayMAC function with non-valid mac address")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1338,7 +1381,7 @@ func TestWithGatewayMAC_NonValidMAC(t *testing.T) {
 
 func TestWithGatewayMAC_NormalBehavior(t *testing.T) {
 	t.Log("Test WithGatewayMAC function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1367,7 +1410,7 @@ func TestWithGatewayMAC_NormalBehavior(t *testing.T) {
 
 func TestWithSourceMAC_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithSourceMAC function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1402,7 +1445,7 @@ func TestWithSourceMAC_MultiplePassing(t *testing.T) {
 
 func TestWithSourceMAC_NonValidMAC(t *testing.T) {
 	t.Log("Testing WithSourceMAC function with non-valid mac address")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1417,7 +1460,7 @@ func TestWithSourceMAC_NonValidMAC(t *testing.T) {
 
 func TestWithSourceMAC_NormalBehavior(t *testing.T) {
 	t.Log("Test WithSourceMAC function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1446,7 +1489,7 @@ func TestWithSourceMAC_NormalBehavior(t *testing.T) {
 
 func TestWithInterface_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithInterface function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1481,7 +1524,7 @@ func TestWithInterface_MultiplePassing(t *testing.T) {
 
 func TestWithInterface_NonAvailableInterface(t *testing.T) {
 	t.Log("Testing WithInterface function with non-available interface name on system")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1496,7 +1539,7 @@ func TestWithInterface_NonAvailableInterface(t *testing.T) {
 
 func TestWithInterface_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithInterface function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1524,7 +1567,7 @@ func TestWithInterface_NormalBehavior(t *testing.T) {
 
 func TestWithVPN_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithVPN function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1545,7 +1588,7 @@ func TestWithVPN_MultiplePassing(t *testing.T) {
 
 func TestWithVPN_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithVPN function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1560,7 +1603,7 @@ func TestWithVPN_NormalBehavior(t *testing.T) {
 
 func TestWithProbeModule_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithProbeModule function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1581,7 +1624,7 @@ func TestWithProbeModule_MultiplePassing(t *testing.T) {
 
 func TestWithProbeModule_NonAvailableModule(t *testing.T) {
 	t.Log("Testing WithProbeModule function with non-available probe module name")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1596,7 +1639,7 @@ func TestWithProbeModule_NonAvailableModule(t *testing.T) {
 
 func TestWithProbeModule_EmptyString(t *testing.T) {
 	t.Log("Testing WithProbeModule function with empty probe module name")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1611,7 +1654,7 @@ func TestWithProbeModule_EmptyString(t *testing.T) {
 
 func TestWithProbeModule_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithProbeModule function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1626,7 +1669,7 @@ func TestWithProbeModule_NormalBehavior(t *testing.T) {
 
 func TestWithProbeArgs_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithProbeArgs function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1647,7 +1690,7 @@ func TestWithProbeArgs_MultiplePassing(t *testing.T) {
 
 func TestWithProbeArgs_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithProbeArgs function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1661,7 +1704,7 @@ func TestWithProbeArgs_NormalBehavior(t *testing.T) {
 
 func TestWithOutputFields_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithOutputFields function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1683,7 +1726,7 @@ func TestWithOutputFields_MultiplePassing(t *testing.T) {
 
 func TestWithOutputFields_NonAvailableModule(t *testing.T) {
 	t.Log("Testing WithOutputFields function with non-available probe module name")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1699,7 +1742,7 @@ func TestWithOutputFields_NonAvailableModule(t *testing.T) {
 
 func TestWithOutputFields_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithOutputFields function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1715,7 +1758,7 @@ func TestWithOutputFields_NormalBehavior(t *testing.T) {
 
 func TestWithOutputModule_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithOutputModule function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1736,7 +1779,7 @@ func TestWithOutputModule_MultiplePassing(t *testing.T) {
 
 func TestWithOutputModule_NonAvailableModule(t *testing.T) {
 	t.Log("Testing WithOutputModule function with non-available probe module name")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1751,7 +1794,7 @@ func TestWithOutputModule_NonAvailableModule(t *testing.T) {
 
 func TestWithOutputModule_EmptyString(t *testing.T) {
 	t.Log("Testing WithOutputModule function with empty probe module name")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1766,7 +1809,7 @@ func TestWithOutputModule_EmptyString(t *testing.T) {
 
 func TestWithOutputModule_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithOutputModule function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1781,7 +1824,7 @@ func TestWithOutputModule_NormalBehavior(t *testing.T) {
 
 func TestWithOutputArgs_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithOutputArgs function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1802,7 +1845,7 @@ func TestWithOutputArgs_MultiplePassing(t *testing.T) {
 
 func TestWithOutputArgs_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithOutputArgs function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1817,7 +1860,7 @@ func TestWithOutputArgs_NormalBehavior(t *testing.T) {
 
 func TestWithOutputFilter_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithOutputFilter function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1838,7 +1881,7 @@ func TestWithOutputFilter_MultiplePassing(t *testing.T) {
 
 func TestWithOutputFilter_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithOutputFilter function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1853,7 +1896,7 @@ func TestWithOutputFilter_NormalBehavior(t *testing.T) {
 
 func TestWithVerbosity_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithVerbosity function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1874,7 +1917,7 @@ func TestWithVerbosity_MultiplePassing(t *testing.T) {
 
 func TestWithVerbosity_WrongLevel(t *testing.T) {
 	t.Log("Testing WithVerbosity function with wrong verbosity level")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1890,7 +1933,7 @@ func TestWithVerbosity_WrongLevel(t *testing.T) {
 
 func TestWithVerbosity_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithVerbosity function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1905,7 +1948,7 @@ func TestWithVerbosity_NormalBehavior(t *testing.T) {
 
 func TestWithLogFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithLogFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1926,7 +1969,7 @@ func TestWithLogFile_MultiplePassing(t *testing.T) {
 
 func TestWithLogFile_LogDirectoryPassed(t *testing.T) {
 	t.Log("Testing WithLogFile function with --log-directory passed")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1944,7 +1987,7 @@ func TestWithLogFile_LogDirectoryPassed(t *testing.T) {
 
 func TestWithLogFile_PathIsADirectory(t *testing.T) {
 	t.Log("Testing WithLogFile function with existing directory as value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1959,7 +2002,7 @@ func TestWithLogFile_PathIsADirectory(t *testing.T) {
 
 func TestWithLogFile_ParentDirNotExists(t *testing.T) {
 	t.Log("Testing WithLogFile function with given path's parent directory not exists")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1974,7 +2017,7 @@ func TestWithLogFile_ParentDirNotExists(t *testing.T) {
 
 func TestWithLogFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithLogFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -1989,7 +2032,7 @@ func TestWithLogFile_NormalBehavior(t *testing.T) {
 
 func TestWithLogDirectory_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithLogDirectory function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2010,7 +2053,7 @@ func TestWithLogDirectory_MultiplePassing(t *testing.T) {
 
 func TestWithLogDirectory_LogFilePassed(t *testing.T) {
 	t.Log("Testing WithLogDirectory function with --log-file passed")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2028,7 +2071,7 @@ func TestWithLogDirectory_LogFilePassed(t *testing.T) {
 
 func TestWithLogDirectory_NonExistingPath(t *testing.T) {
 	t.Log("Testing WithLogDirectory function with non-existing path")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2045,7 +2088,7 @@ func TestWithLogDirectory_NonExistingPath(t *testing.T) {
 
 func TestWithLogDirectory_PathIsNotDirectory(t *testing.T) {
 	t.Log("Testing WithLogDirectory function with path is not directory")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2062,7 +2105,7 @@ func TestWithLogDirectory_PathIsNotDirectory(t *testing.T) {
 
 func TestWithLogDirectory_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithLogDirectory function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2079,7 +2122,7 @@ func TestWithLogDirectory_NormalBehavior(t *testing.T) {
 
 func TestWithMetadataFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMetadataFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2100,7 +2143,7 @@ func TestWithMetadataFile_MultiplePassing(t *testing.T) {
 
 func TestWithMetadataFile_PathIsADirectory(t *testing.T) {
 	t.Log("Testing WithMetadataFile function with existing directory as value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2115,7 +2158,7 @@ func TestWithMetadataFile_PathIsADirectory(t *testing.T) {
 
 func TestWithMetadataFile_ParentDirNotExists(t *testing.T) {
 	t.Log("Testing WithMetadataFile function with given path's parent directory not exists")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2130,7 +2173,7 @@ func TestWithMetadataFile_ParentDirNotExists(t *testing.T) {
 
 func TestWithMetadataFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMetadataFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2145,7 +2188,7 @@ func TestWithMetadataFile_NormalBehavior(t *testing.T) {
 
 func TestWithStatusUpdatesFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithStatusUpdatesFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2166,7 +2209,7 @@ func TestWithStatusUpdatesFile_MultiplePassing(t *testing.T) {
 
 func TestWithStatusUpdatesFile_PathIsADirectory(t *testing.T) {
 	t.Log("Testing WithStatusUpdatesFile function with existing directory as value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2181,7 +2224,7 @@ func TestWithStatusUpdatesFile_PathIsADirectory(t *testing.T) {
 
 func TestWithStatusUpdatesFile_ParentDirNotExists(t *testing.T) {
 	t.Log("Testing WithStatusUpdatesFile function with given path's parent directory not exists")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2196,7 +2239,7 @@ func TestWithStatusUpdatesFile_ParentDirNotExists(t *testing.T) {
 
 func TestWithStatusUpdatesFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithStatusUpdatesFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2211,7 +2254,7 @@ func TestWithStatusUpdatesFile_NormalBehavior(t *testing.T) {
 
 func TestWithQuiet_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithQuiet function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2232,7 +2275,7 @@ func TestWithQuiet_MultiplePassing(t *testing.T) {
 
 func TestWithQuiet_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithQuiet function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2247,7 +2290,7 @@ func TestWithQuiet_NormalBehavior(t *testing.T) {
 
 func TestWithDisableSyslog_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithDisableSyslog function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2268,7 +2311,7 @@ func TestWithDisableSyslog_MultiplePassing(t *testing.T) {
 
 func TestWithDisableSyslog_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithDisableSyslog function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2283,7 +2326,7 @@ func TestWithDisableSyslog_NormalBehavior(t *testing.T) {
 
 func TestWithNotes_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithNotes function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2304,7 +2347,7 @@ func TestWithNotes_MultiplePassing(t *testing.T) {
 
 func TestWithNotes_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithNotes function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2319,7 +2362,7 @@ func TestWithNotes_NormalBehavior(t *testing.T) {
 
 func TestWithUserMetadata_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithUserMetadata function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2340,7 +2383,7 @@ func TestWithUserMetadata_MultiplePassing(t *testing.T) {
 
 func TestWithUserMetadata_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithUserMetadata function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2355,7 +2398,7 @@ func TestWithUserMetadata_NormalBehavior(t *testing.T) {
 
 func TestWithConfigFile_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithConfigFile function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2376,7 +2419,7 @@ func TestWithConfigFile_MultiplePassing(t *testing.T) {
 
 func TestWithConfigFile_NonExistingFile(t *testing.T) {
 	t.Log("Testing WithConfigFile function with non-existing file path")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2391,7 +2434,7 @@ func TestWithConfigFile_NonExistingFile(t *testing.T) {
 
 func TestWithConfigFile_WithDirectoryPath(t *testing.T) {
 	t.Log("Testing WithConfigFile function with directory path")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2406,7 +2449,7 @@ func TestWithConfigFile_WithDirectoryPath(t *testing.T) {
 
 func TestWithConfigFile_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithConfigFile function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2421,7 +2464,7 @@ func TestWithConfigFile_NormalBehavior(t *testing.T) {
 
 func TestWithMaxSendtoFailures_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMaxSendtoFailures function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2442,7 +2485,7 @@ func TestWithMaxSendtoFailures_MultiplePassing(t *testing.T) {
 
 func TestWithMaxSendtoFailures_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithMaxSendtoFailures function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2457,7 +2500,7 @@ func TestWithMaxSendtoFailures_NonNumericValue(t *testing.T) {
 
 func TestWithMaxSendtoFailures_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithMaxSendtoFailures function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2472,7 +2515,7 @@ func TestWithMaxSendtoFailures_NormalBehavior(t *testing.T) {
 
 func TestWithMinHitrate_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithMinHitrate function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2493,7 +2536,7 @@ func TestWithMinHitrate_MultiplePassing(t *testing.T) {
 
 func TestWithMinHitrate_NonDecimalValue(t *testing.T) {
 	t.Log("Testing WithMinHitrate function with non-decimal value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2508,7 +2551,7 @@ func TestWithMinHitrate_NonDecimalValue(t *testing.T) {
 
 func TestWithMinHitrate_NormalBehavior_With_Decimal(t *testing.T) {
 	t.Log("Testing WithMinHitrate function under normal behavior with decimal")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2523,7 +2566,7 @@ func TestWithMinHitrate_NormalBehavior_With_Decimal(t *testing.T) {
 
 func TestWithMinHitrate_NormalBehavior_With_Integer(t *testing.T) {
 	t.Log("Testing WithMinHitrate function under normal behavior with integer")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2538,7 +2581,7 @@ func TestWithMinHitrate_NormalBehavior_With_Integer(t *testing.T) {
 
 func TestWithSenderThreads_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithSenderThreads function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2559,7 +2602,7 @@ func TestWithSenderThreads_MultiplePassing(t *testing.T) {
 
 func TestWithSenderThreads_NonNumericValue(t *testing.T) {
 	t.Log("Testing WithSenderThreads function with non-numeric value")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2574,7 +2617,7 @@ func TestWithSenderThreads_NonNumericValue(t *testing.T) {
 
 func TestWithSenderThreads_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithSenderThreads function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2589,7 +2632,7 @@ func TestWithSenderThreads_NormalBehavior(t *testing.T) {
 
 func TestWithCores_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithCores function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2615,7 +2658,7 @@ func TestWithCores_MultiplePassing(t *testing.T) {
 
 func TestWithCores_NonExistingCoreIndex(t *testing.T) {
 	t.Log("Testing WithCores function with non-existing core index")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2634,7 +2677,7 @@ func TestWithCores_NonExistingCoreIndex(t *testing.T) {
 
 func TestWithCores_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithCores function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2653,7 +2696,7 @@ func TestWithCores_NormalBehavior(t *testing.T) {
 
 func TestWithIgnoreInvalidHosts_MultiplePassing(t *testing.T) {
 	t.Log("Testing WithIgnoreInvalidHosts function with passing multiple time")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2674,7 +2717,7 @@ func TestWithIgnoreInvalidHosts_MultiplePassing(t *testing.T) {
 
 func TestWithIgnoreInvalidHosts_NormalBehavior(t *testing.T) {
 	t.Log("Testing WithIgnoreInvalidHosts function under normal behavior")
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	if err != nil {
 		t.Error("Cannot create zmapgo scanner to test")
 	}
@@ -2686,3 +2729,424 @@ func TestWithIgnoreInvalidHosts_NormalBehavior(t *testing.T) {
 		t.Error("Expected that error is not returned while under normal behavior")
 	}
 }
+
+func TestIPv6Options_AddressFamilies(t *testing.T) {
+	t.Log("Testing ipv6 options with address family checks")
+	targetFile := filepath.Join(t.TempDir(), "targets.txt")
+	if err := ioutil.WriteFile(targetFile, []byte("2001:db8::1\n"), 0644); err != nil {
+		t.Fatalf("Cannot create ipv6 target file: %v", err)
+	}
+
+	tests := []struct {
+		testDesc        string
+		options         []Option
+		isErrorExpected bool
+	}{
+		{
+			testDesc:        "With IPv6 Target File, Source IP And Probe Module",
+			options:         []Option{WithIPv6TargetFile(targetFile), WithIPv6SourceIP("2001:db8::2"), WithProbeModule(ProbeModuleIPv6TCPSynScan)},
+			isErrorExpected: false,
+		},
+		{
+			testDesc:        "With Not Existing IPv6 Target File",
+			options:         []Option{WithIPv6TargetFile("/path/to/not/exists")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 Address As IPv6 Source IP",
+			options:         []Option{WithIPv6SourceIP("192.168.1.1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 Targets Before IPv6 Target File",
+			options:         []Option{WithTargets("192.168.1.0/24"), WithIPv6TargetFile(targetFile)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Source IP Before IPv4 Targets",
+			options:         []Option{WithIPv6SourceIP("2001:db8::2"), WithTargets("192.168.1.0/24")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 Source IP Before IPv6 Source IP",
+			options:         []Option{WithSourceIP("192.168.1.1"), WithIPv6SourceIP("2001:db8::2")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Target File Before IPv4 Source IP",
+			options:         []Option{WithIPv6TargetFile(targetFile), WithSourceIP("192.168.1.1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Probe Module Before IPv4 Targets",
+			options:         []Option{WithProbeModule(ProbeModuleICMPv6Echo), WithTargets("192.168.1.1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 Probe Module Before IPv6 Target File",
+			options:         []Option{WithProbeModule("tcp_synscan"), WithIPv6TargetFile(targetFile)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Target File Before IPv4 Probe Module",
+			options:         []Option{WithIPv6TargetFile(targetFile), WithProbeModule("tcp_synscan")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Targets, Source IP And Probe Module",
+			options:         []Option{WithTargets("2001:db8::1"), WithIPv6SourceIP("2001:db8::2"), WithProbeModule(ProbeModuleIPv6TCPSynScan)},
+			isErrorExpected: false,
+		},
+		{
+			testDesc:        "With IPv4 Targets Before IPv6 Targets",
+			options:         []Option{WithTargets("192.168.1.1"), WithTargets("2001:db8::1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Targets Before IPv4 Targets",
+			options:         []Option{WithTargets("2001:db8::1"), WithTargets("192.168.1.1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv4 Source IP Before IPv6 Targets",
+			options:         []Option{WithSourceIP("192.168.1.1"), WithTargets("2001:db8::1")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Targets Before IPv4 Probe Module",
+			options:         []Option{WithTargets("2001:db8::1"), WithProbeModule("tcp_synscan")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Targets And IPv6 Target File",
+			options:         []Option{WithTargets("2001:db8::1"), WithIPv6TargetFile(targetFile)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Target File And IPv6 Targets",
+			options:         []Option{WithIPv6TargetFile(targetFile), WithTargets("2001:db8::1")},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{
+				Version:      "4.0.0",
+				ProbeModules: []string{"tcp_synscan", ProbeModuleIPv6TCPSynScan, ProbeModuleICMPv6Echo},
+			})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = scanner.AddOptions(test.options...)
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+		})
+	}
+}
+
+func TestWithTargetPorts(t *testing.T) {
+	t.Log("Testing WithTargetPorts function")
+	tests := []struct {
+		testDesc        string
+		version         string
+		options         []Option
+		expectedArgs    []string
+		isErrorExpected bool
+	}{
+		{
+			testDesc:     "With Lists And Ranges",
+			version:      "4.1.0",
+			options:      []Option{WithTargetPorts("80,443", "8000-8100")},
+			expectedArgs: []string{"--target-ports", "80,443,8000-8100"},
+		},
+		{
+			testDesc:        "With Old Zmap",
+			version:         "2.1.1",
+			options:         []Option{WithTargetPorts("80,443")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Zmap 3",
+			version:         "3.0.0",
+			options:         []Option{WithTargetPorts("80,443")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Wrong Range",
+			version:         "4.1.0",
+			options:         []Option{WithTargetPorts("8100-8000")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Out Of Range Port",
+			version:         "4.1.0",
+			options:         []Option{WithTargetPorts("80,65536")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Non Numeric Port",
+			version:         "4.1.0",
+			options:         []Option{WithTargetPorts("http")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Target Port",
+			version:         "4.1.0",
+			options:         []Option{WithTargetPort("80"), WithTargetPorts("443")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Target Port After Target Ports",
+			version:         "4.1.0",
+			options:         []Option{WithTargetPorts("443"), WithTargetPort("80")},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{Version: test.version})
+			s, err := newScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = s.AddOptions(test.options...)
+			t.Logf("Returned Error: %v", err)
+			if !assert.Equal(t, test.isErrorExpected, err != nil) || err != nil {
+				return
+			}
+			assert.Equal(t, test.expectedArgs, s.args.strings())
+		})
+	}
+}
+
+func TestWithTargetPorts_TagsDport(t *testing.T) {
+	t.Log("Testing WithTargetPorts function adds dport to output fields")
+	binary := zmaptest.New(t, zmaptest.Config{Version: "4.1.0"})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	err = scanner.AddOptions(WithTargetPorts("80,443"), WithOutputFields([]string{"saddr"}))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+
+	value, ok := getArgumentValue(binary.LastInvocation(), "--output-fields")
+	assert.True(t, ok)
+	assert.Equal(t, "saddr,dport", value)
+}
+
+func TestFeatureOptions(t *testing.T) {
+	t.Log("Testing options that require newer zmap releases")
+	tests := []struct {
+		testDesc        string
+		version         string
+		options         []Option
+		isErrorExpected bool
+	}{
+		{
+			testDesc: "With IPLayer On Zmap 3.0.0",
+			version:  "3.0.0",
+			options:  []Option{WithIPLayer()},
+		},
+		{
+			testDesc:        "With IPLayer On Zmap 2.1.1",
+			version:         "2.1.1",
+			options:         []Option{WithIPLayer()},
+			isErrorExpected: true,
+		},
+		{
+			testDesc: "With Dedup On Zmap 4.0.0",
+			version:  "4.0.0",
+			options:  []Option{WithDedupMethod(DedupMethodWindow), WithDedupWindowSize("1000000")},
+		},
+		{
+			testDesc:        "With Dedup On Zmap 3.0.0",
+			version:         "3.0.0",
+			options:         []Option{WithDedupMethod(DedupMethodFull)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Wrong Dedup Method",
+			version:         "4.0.0",
+			options:         []Option{WithDedupMethod("bloom")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Wrong Dedup Window Size",
+			version:         "4.0.0",
+			options:         []Option{WithDedupWindowSize("0")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With IPv6 Source IP On Zmap 3.0.0",
+			version:         "3.0.0",
+			options:         []Option{WithIPv6SourceIP("2001:db8::1")},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{Version: test.version})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = scanner.AddOptions(test.options...)
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+		})
+	}
+}
+
+func TestWithListOfIPsFile(t *testing.T) {
+	t.Log("Testing WithListOfIPsFile function")
+	listFile := filepath.Join(t.TempDir(), "ips.txt")
+	if err := ioutil.WriteFile(listFile, []byte("192.168.1.1\n"), 0644); err != nil {
+		t.Fatalf("Cannot create list of ips file: %v", err)
+	}
+
+	tests := []struct {
+		testDesc        string
+		version         string
+		options         []Option
+		isErrorExpected bool
+	}{
+		{
+			testDesc: "With Zmap 3.0.0",
+			version:  "3.0.0",
+			options:  []Option{WithListOfIPsFile(listFile)},
+		},
+		{
+			testDesc:        "With Zmap 2.1.1",
+			version:         "2.1.1",
+			options:         []Option{WithListOfIPsFile(listFile)},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Not Existing File",
+			version:         "3.0.0",
+			options:         []Option{WithListOfIPsFile("/path/to/not/exists")},
+			isErrorExpected: true,
+		},
+		{
+			testDesc:        "With Multiple Passing",
+			version:         "3.0.0",
+			options:         []Option{WithListOfIPsFile(listFile), WithListOfIPsFile(listFile)},
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{Version: test.version})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = scanner.AddOptions(test.options...)
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+		})
+	}
+}
+
+func TestWithWhitelistFile_PassedOnce(t *testing.T) {
+	t.Log("Testing WithWhitelistFile function adds the file path once")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	s, err := newScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	whitelistFile := filepath.Join(t.TempDir(), "whitelist.conf")
+	if err := ioutil.WriteFile(whitelistFile, []byte("10.0.0.0/8\n"), 0644); err != nil {
+		t.Fatalf("Cannot create whitelist file: %v", err)
+	}
+
+	err = s.AddOptions(WithWhitelistFile(whitelistFile))
+	t.Logf("Returned Error: %v", err)
+	assert.Equal(t, []string{"--whitelist-file", whitelistFile}, s.args.strings())
+}
+
+func TestWithOutputFields_ProbeModule(t *testing.T) {
+	tests := []struct {
+		testDesc      string
+		options       []Option
+		expectedError bool
+		expectedValid bool
+	}{
+		{
+			testDesc:      "Probe Module Fields After Probe Module",
+			options:       []Option{WithProbeModule("icmp_echoscan"), WithOutputFields([]string{"saddr", "type", "data"})},
+			expectedError: false,
+			expectedValid: true,
+		},
+		{
+			testDesc:      "Probe Module Fields Before Probe Module",
+			options:       []Option{WithOutputFields([]string{"saddr", "type", "data"}), WithProbeModule("icmp_echoscan")},
+			expectedError: false,
+			expectedValid: true,
+		},
+		{
+			testDesc:      "Other Probe Module Fields After Probe Module",
+			options:       []Option{WithProbeModule("icmp_echoscan"), WithOutputFields([]string{"saddr", "sport"})},
+			expectedError: true,
+		},
+		{
+			testDesc:      "Other Probe Module Fields Before Probe Module",
+			options:       []Option{WithOutputFields([]string{"saddr", "sport"}), WithProbeModule("icmp_echoscan")},
+			expectedError: false,
+			expectedValid: false,
+		},
+		{
+			testDesc:      "Probe Module Fields Without Probe Module",
+			options:       []Option{WithTargetPort("80"), WithOutputFields([]string{"saddr", "type"})},
+			expectedError: false,
+			expectedValid: false,
+		},
+		{
+			testDesc:      "Unknown Fields Without Probe Module",
+			options:       []Option{WithOutputFields([]string{"saddr", "unknown"})},
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{ModuleOutputFields: icmpOutputFields})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			err = scanner.AddOptions(test.options...)
+			t.Logf("Returned Error: %v", err)
+			if test.expectedError {
+				if err == nil {
+					t.Error("Expected that error is returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal("Expected that error is not returned")
+			}
+
+			err = scanner.Validate()
+			t.Logf("Returned Validation Error: %v", err)
+			assert.Equal(t, test.expectedValid, err == nil)
+		})
+	}
+}
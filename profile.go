@@ -0,0 +1,290 @@
+package zmapgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScanProfile is a declarative form of the scanner options, which can be kept in YAML or JSON files.
+// Every field is the option with the same name in options.go and zero values are not passed.
+// Field names in files are the zmap flag names. Ex: "target-port", "output-fields"
+// Fields have toml tags as well, but there is no TOML loader. TOML files can be decoded into a ScanProfile
+// with any TOML library that reads toml tags.
+type ScanProfile struct {
+	// Basic Arguments
+	Targets        []string `yaml:"targets,omitempty" json:"targets,omitempty" toml:"targets,omitempty"`
+	TargetPort     string   `yaml:"target-port,omitempty" json:"target-port,omitempty" toml:"target-port,omitempty"`
+	TargetPorts    []string `yaml:"target-ports,omitempty" json:"target-ports,omitempty" toml:"target-ports,omitempty"`
+	OutputFile     string   `yaml:"output-file,omitempty" json:"output-file,omitempty" toml:"output-file,omitempty"`
+	BlacklistFile  string   `yaml:"blacklist-file,omitempty" json:"blacklist-file,omitempty" toml:"blacklist-file,omitempty"`
+	WhitelistFile  string   `yaml:"whitelist-file,omitempty" json:"whitelist-file,omitempty" toml:"whitelist-file,omitempty"`
+	Blocklist      []string `yaml:"blocklist,omitempty" json:"blocklist,omitempty" toml:"blocklist,omitempty"`
+	Allowlist      []string `yaml:"allowlist,omitempty" json:"allowlist,omitempty" toml:"allowlist,omitempty"`
+	ListOfIPsFile  string   `yaml:"list-of-ips-file,omitempty" json:"list-of-ips-file,omitempty" toml:"list-of-ips-file,omitempty"`
+	IPv6TargetFile string   `yaml:"ipv6-target-file,omitempty" json:"ipv6-target-file,omitempty" toml:"ipv6-target-file,omitempty"`
+	IPv6SourceIP   string   `yaml:"ipv6-source-ip,omitempty" json:"ipv6-source-ip,omitempty" toml:"ipv6-source-ip,omitempty"`
+
+	// Scan Options
+	Rate string `yaml:"rate,omitempty" json:"rate,omitempty" toml:"rate,omitempty"`
+	// Bandwidth is a number with an optional B, K, M or G suffix. Ex: "10M"
+	Bandwidth string `yaml:"bandwidth,omitempty" json:"bandwidth,omitempty" toml:"bandwidth,omitempty"`
+	// MaxTargets is a number or a percentage of the address space. Ex: "1000", "10%"
+	MaxTargets string `yaml:"max-targets,omitempty" json:"max-targets,omitempty" toml:"max-targets,omitempty"`
+	MaxRuntime string `yaml:"max-runtime,omitempty" json:"max-runtime,omitempty" toml:"max-runtime,omitempty"`
+	MaxResults string `yaml:"max-results,omitempty" json:"max-results,omitempty" toml:"max-results,omitempty"`
+	Probes     string `yaml:"probes,omitempty" json:"probes,omitempty" toml:"probes,omitempty"`
+	Cooldown   string `yaml:"cooldown-time,omitempty" json:"cooldown-time,omitempty" toml:"cooldown-time,omitempty"`
+	Seed       string `yaml:"seed,omitempty" json:"seed,omitempty" toml:"seed,omitempty"`
+	Retries    string `yaml:"retries,omitempty" json:"retries,omitempty" toml:"retries,omitempty"`
+	Dryrun     bool   `yaml:"dryrun,omitempty" json:"dryrun,omitempty" toml:"dryrun,omitempty"`
+	Shards     string `yaml:"shards,omitempty" json:"shards,omitempty" toml:"shards,omitempty"`
+	Shard      string `yaml:"shard,omitempty" json:"shard,omitempty" toml:"shard,omitempty"`
+
+	// Network Options
+	SourcePort string `yaml:"source-port,omitempty" json:"source-port,omitempty" toml:"source-port,omitempty"`
+	SourceIP   string `yaml:"source-ip,omitempty" json:"source-ip,omitempty" toml:"source-ip,omitempty"`
+	GatewayMAC string `yaml:"gateway-mac,omitempty" json:"gateway-mac,omitempty" toml:"gateway-mac,omitempty"`
+	SourceMAC  string `yaml:"source-mac,omitempty" json:"source-mac,omitempty" toml:"source-mac,omitempty"`
+	Interface  string `yaml:"interface,omitempty" json:"interface,omitempty" toml:"interface,omitempty"`
+	VPN        bool   `yaml:"vpn,omitempty" json:"vpn,omitempty" toml:"vpn,omitempty"`
+	IPLayer    bool   `yaml:"iplayer,omitempty" json:"iplayer,omitempty" toml:"iplayer,omitempty"`
+
+	// Probe Modules
+	ProbeModule string `yaml:"probe-module,omitempty" json:"probe-module,omitempty" toml:"probe-module,omitempty"`
+	ProbeArgs   string `yaml:"probe-args,omitempty" json:"probe-args,omitempty" toml:"probe-args,omitempty"`
+
+	// Data Output
+	OutputFields    []string `yaml:"output-fields,omitempty" json:"output-fields,omitempty" toml:"output-fields,omitempty"`
+	OutputModule    string   `yaml:"output-module,omitempty" json:"output-module,omitempty" toml:"output-module,omitempty"`
+	OutputArgs      string   `yaml:"output-args,omitempty" json:"output-args,omitempty" toml:"output-args,omitempty"`
+	OutputFilter    string   `yaml:"output-filter,omitempty" json:"output-filter,omitempty" toml:"output-filter,omitempty"`
+	DedupMethod     string   `yaml:"dedup-method,omitempty" json:"dedup-method,omitempty" toml:"dedup-method,omitempty"`
+	DedupWindowSize string   `yaml:"dedup-window-size,omitempty" json:"dedup-window-size,omitempty" toml:"dedup-window-size,omitempty"`
+
+	// Logging and Metadata
+	Verbosity         string `yaml:"verbosity,omitempty" json:"verbosity,omitempty" toml:"verbosity,omitempty"`
+	LogFile           string `yaml:"log-file,omitempty" json:"log-file,omitempty" toml:"log-file,omitempty"`
+	LogDirectory      string `yaml:"log-directory,omitempty" json:"log-directory,omitempty" toml:"log-directory,omitempty"`
+	MetadataFile      string `yaml:"metadata-file,omitempty" json:"metadata-file,omitempty" toml:"metadata-file,omitempty"`
+	StatusUpdatesFile string `yaml:"status-updates-file,omitempty" json:"status-updates-file,omitempty" toml:"status-updates-file,omitempty"`
+	Quiet             bool   `yaml:"quiet,omitempty" json:"quiet,omitempty" toml:"quiet,omitempty"`
+	DisableSyslog     bool   `yaml:"disable-syslog,omitempty" json:"disable-syslog,omitempty" toml:"disable-syslog,omitempty"`
+	Notes             string `yaml:"notes,omitempty" json:"notes,omitempty" toml:"notes,omitempty"`
+	UserMetadata      string `yaml:"user-metadata,omitempty" json:"user-metadata,omitempty" toml:"user-metadata,omitempty"`
+
+	// Additional Options
+	ConfigFile         string   `yaml:"config,omitempty" json:"config,omitempty" toml:"config,omitempty"`
+	MaxSendtoFailures  string   `yaml:"max-sendto-failures,omitempty" json:"max-sendto-failures,omitempty" toml:"max-sendto-failures,omitempty"`
+	MinHitrate         string   `yaml:"min-hitrate,omitempty" json:"min-hitrate,omitempty" toml:"min-hitrate,omitempty"`
+	SenderThreads      string   `yaml:"sender-threads,omitempty" json:"sender-threads,omitempty" toml:"sender-threads,omitempty"`
+	Cores              []string `yaml:"cores,omitempty" json:"cores,omitempty" toml:"cores,omitempty"`
+	IgnoreInvalidHosts bool     `yaml:"ignore-invalid-hosts,omitempty" json:"ignore-invalid-hosts,omitempty" toml:"ignore-invalid-hosts,omitempty"`
+
+	// CustomArguments are passed with WithCustomArguments after every other option.
+	CustomArguments []string `yaml:"custom-arguments,omitempty" json:"custom-arguments,omitempty" toml:"custom-arguments,omitempty"`
+}
+
+// LoadScanProfile reads a scan profile from a YAML or JSON file.
+// The format is chosen by the file extension (.yaml, .yml or .json).
+func LoadScanProfile(path string) (*ScanProfile, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var profile *ScanProfile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		profile, err = ParseScanProfileYAML(bytes.NewReader(content))
+	case ".json":
+		profile, err = ParseScanProfileJSON(bytes.NewReader(content))
+	default:
+		return nil, fmt.Errorf("unsupported scan profile extension of %s. Supported extensions: (.yaml,.yml,.json)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// ParseScanProfileYAML parses a scan profile in YAML format. Unknown fields are rejected.
+func ParseScanProfileYAML(ioReader io.Reader) (*ScanProfile, error) {
+	decoder := yaml.NewDecoder(ioReader)
+	decoder.KnownFields(true)
+
+	profile := &ScanProfile{}
+	if err := decoder.Decode(profile); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// ParseScanProfileJSON parses a scan profile in JSON format. Unknown fields are rejected.
+func ParseScanProfileJSON(ioReader io.Reader) (*ScanProfile, error) {
+	decoder := json.NewDecoder(ioReader)
+	decoder.DisallowUnknownFields()
+
+	profile := &ScanProfile{}
+	if err := decoder.Decode(profile); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// Options returns the options of the profile in the order of options.go.
+// Values are validated by the options when they are added to a scanner, and the errors
+// are prefixed with the field name. Ex: "rate: given rate value is not a numeric value"
+func (p *ScanProfile) Options() ([]Option, error) {
+	var options []Option
+	add := func(name string, option Option) {
+		options = append(options, profileOption(name, option))
+	}
+	addString := func(name string, value string, newOption func(string) Option) {
+		if value != "" {
+			add(name, newOption(value))
+		}
+	}
+	addFlag := func(name string, value bool, newOption func() Option) {
+		if value {
+			add(name, newOption())
+		}
+	}
+
+	// Basic Arguments
+	if len(p.Targets) > 0 {
+		add("targets", WithTargets(p.Targets...))
+	}
+	addString("target-port", p.TargetPort, WithTargetPort)
+	if len(p.TargetPorts) > 0 {
+		add("target-ports", WithTargetPorts(p.TargetPorts...))
+	}
+	addString("output-file", p.OutputFile, WithOutputFile)
+	addString("blacklist-file", p.BlacklistFile, WithBlacklistFile)
+	addString("whitelist-file", p.WhitelistFile, WithWhitelistFile)
+	if len(p.Blocklist) > 0 {
+		blocklist, err := profileBlocklist(p.Blocklist)
+		if err != nil {
+			return nil, fmt.Errorf("blocklist: %w", err)
+		}
+		add("blocklist", WithBlocklist(blocklist))
+	}
+	if len(p.Allowlist) > 0 {
+		allowlist, err := profileBlocklist(p.Allowlist)
+		if err != nil {
+			return nil, fmt.Errorf("allowlist: %w", err)
+		}
+		add("allowlist", WithAllowlist(allowlist))
+	}
+	addString("list-of-ips-file", p.ListOfIPsFile, WithListOfIPsFile)
+	addString("ipv6-target-file", p.IPv6TargetFile, WithIPv6TargetFile)
+	addString("ipv6-source-ip", p.IPv6SourceIP, WithIPv6SourceIP)
+
+	// Scan Options
+	addString("rate", p.Rate, WithRate)
+	if p.Bandwidth != "" {
+		bandwidth, unit := splitBandwidth(p.Bandwidth)
+		add("bandwidth", WithBandwidth(bandwidth, unit))
+	}
+	if p.MaxTargets != "" {
+		add("max-targets", WithMaxTargets(strings.TrimSuffix(p.MaxTargets, "%"), strings.HasSuffix(p.MaxTargets, "%")))
+	}
+	addString("max-runtime", p.MaxRuntime, WithMaxRuntime)
+	addString("max-results", p.MaxResults, WithMaxResults)
+	addString("probes", p.Probes, WithNumberOfProbesPerIP)
+	addString("cooldown-time", p.Cooldown, WithCooldownTime)
+	addString("seed", p.Seed, WithSeed)
+	addString("retries", p.Retries, WithMaxRetries)
+	addFlag("dryrun", p.Dryrun, WithDryrun)
+	addString("shards", p.Shards, WithTotalShards)
+	addString("shard", p.Shard, WithShardID)
+
+	// Network Options
+	addString("source-port", p.SourcePort, WithSourcePort)
+	addString("source-ip", p.SourceIP, WithSourceIP)
+	addString("gateway-mac", p.GatewayMAC, WithGatewayMAC)
+	addString("source-mac", p.SourceMAC, WithSourceMAC)
+	addString("interface", p.Interface, WithInterface)
+	addFlag("vpn", p.VPN, WithVPN)
+	addFlag("iplayer", p.IPLayer, WithIPLayer)
+
+	// Probe Modules
+	addString("probe-module", p.ProbeModule, WithProbeModule)
+	addString("probe-args", p.ProbeArgs, WithProbeArgs)
+
+	// Data Output
+	if len(p.OutputFields) > 0 {
+		add("output-fields", WithOutputFields(p.OutputFields))
+	}
+	addString("output-module", p.OutputModule, WithOutputModule)
+	addString("output-args", p.OutputArgs, WithOutputArgs)
+	addString("output-filter", p.OutputFilter, WithOutputFilter)
+	if p.DedupMethod != "" {
+		add("dedup-method", WithDedupMethod(DedupMethod(p.DedupMethod)))
+	}
+	addString("dedup-window-size", p.DedupWindowSize, WithDedupWindowSize)
+
+	// Logging and Metadata
+	if p.Verbosity != "" {
+		add("verbosity", WithVerbosity(VerbosityLevel(p.Verbosity)))
+	}
+	addString("log-file", p.LogFile, WithLogFile)
+	addString("log-directory", p.LogDirectory, WithLogDirectory)
+	addString("metadata-file", p.MetadataFile, WithMetadataFile)
+	addString("status-updates-file", p.StatusUpdatesFile, WithStatusUpdatesFile)
+	addFlag("quiet", p.Quiet, WithQuiet)
+	addFlag("disable-syslog", p.DisableSyslog, WithDisableSyslog)
+	addString("notes", p.Notes, WithNotes)
+	addString("user-metadata", p.UserMetadata, WithUserMetadata)
+
+	// Additional Options
+	addString("config", p.ConfigFile, WithConfigFile)
+	addString("max-sendto-failures", p.MaxSendtoFailures, WithMaxSendtoFailures)
+	addString("min-hitrate", p.MinHitrate, WithMinHitrate)
+	addString("sender-threads", p.SenderThreads, WithSenderThreads)
+	if len(p.Cores) > 0 {
+		add("cores", WithCores(p.Cores))
+	}
+	addFlag("ignore-invalid-hosts", p.IgnoreInvalidHosts, WithIgnoreInvalidHosts)
+
+	if len(p.CustomArguments) > 0 {
+		add("custom-arguments", WithCustomArguments(p.CustomArguments...))
+	}
+	return options, nil
+}
+
+// profileOption prefixes the error of option with the profile field name.
+func profileOption(name string, option Option) Option {
+	return func(s *scanner) error {
+		if err := option(s); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+}
+
+// profileBlocklist builds a blocklist from entries in blacklist file format. Ex: "10.0.0.0/8 # private"
+func profileBlocklist(entries []string) (*Blocklist, error) {
+	blocklist := NewBlocklist()
+	if err := blocklist.AddReader(strings.NewReader(strings.Join(entries, "\n"))); err != nil {
+		return nil, err
+	}
+	return blocklist, nil
+}
+
+// splitBandwidth splits the unit suffix of a bandwidth value. Ex: "10M" -> "10", "M"
+func splitBandwidth(bandwidth string) (string, BandwidthUnit) {
+	for _, unit := range []BandwidthUnit{UnitBandwidthBps, UnitBandwidthKbps, UnitBandwidthMbps, UnitBandwidthGbps} {
+		if strings.HasSuffix(bandwidth, string(unit)) {
+			return strings.TrimSuffix(bandwidth, string(unit)), unit
+		}
+	}
+	return bandwidth, UnitBandwidthBps
+}
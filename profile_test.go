@@ -0,0 +1,230 @@
+package zmapgo
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+const yamlProfile = `
+targets:
+  - 192.168.1.0/24
+target-port: "443"
+blocklist:
+  - "192.168.1.1 # gateway"
+rate: "10000"
+max-targets: "10%"
+probes: "2"
+source-port: 40000-40010
+output-fields: [saddr, sport]
+verbosity: "4"
+dryrun: true
+`
+
+const jsonProfile = `{
+	"targets": ["192.168.1.0/24"],
+	"target-port": "443",
+	"blocklist": ["192.168.1.1 # gateway"],
+	"rate": "10000",
+	"max-targets": "10%",
+	"probes": "2",
+	"source-port": "40000-40010",
+	"output-fields": ["saddr", "sport"],
+	"verbosity": "4",
+	"dryrun": true
+}`
+
+func TestParseScanProfile(t *testing.T) {
+	expectedProfile := &ScanProfile{
+		Targets:      []string{"192.168.1.0/24"},
+		TargetPort:   "443",
+		Blocklist:    []string{"192.168.1.1 # gateway"},
+		Rate:         "10000",
+		MaxTargets:   "10%",
+		Probes:       "2",
+		SourcePort:   "40000-40010",
+		OutputFields: []string{"saddr", "sport"},
+		Verbosity:    "4",
+		Dryrun:       true,
+	}
+
+	tests := []struct {
+		testDesc        string
+		parse           func(content string) (*ScanProfile, error)
+		content         string
+		isErrorExpected bool
+	}{
+		{
+			testDesc: "With YAML",
+			parse: func(content string) (*ScanProfile, error) {
+				return ParseScanProfileYAML(strings.NewReader(content))
+			},
+			content: yamlProfile,
+		},
+		{
+			testDesc: "With JSON",
+			parse: func(content string) (*ScanProfile, error) {
+				return ParseScanProfileJSON(strings.NewReader(content))
+			},
+			content: jsonProfile,
+		},
+		{
+			testDesc: "With Unknown YAML Field",
+			parse: func(content string) (*ScanProfile, error) {
+				return ParseScanProfileYAML(strings.NewReader(content))
+			},
+			content:         "target-prot: 80\n",
+			isErrorExpected: true,
+		},
+		{
+			testDesc: "With Unknown JSON Field",
+			parse: func(content string) (*ScanProfile, error) {
+				return ParseScanProfileJSON(strings.NewReader(content))
+			},
+			content:         `{"target-prot": "80"}`,
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			profile, err := test.parse(test.content)
+			t.Logf("Returned Error: %v", err)
+			if test.isErrorExpected {
+				if err == nil {
+					t.Error("Expected that error is returned")
+				}
+				return
+			}
+			if err != nil {
+				t.Error("Expected that error is not returned")
+			}
+			assert.Equal(t, expectedProfile, profile)
+		})
+	}
+}
+
+func TestLoadScanProfile(t *testing.T) {
+	t.Log("Testing LoadScanProfile function chooses the format by extension")
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "scan.yml")
+	jsonPath := filepath.Join(dir, "scan.json")
+	tomlPath := filepath.Join(dir, "scan.toml")
+	_ = ioutil.WriteFile(yamlPath, []byte(yamlProfile), 0644)
+	_ = ioutil.WriteFile(jsonPath, []byte(jsonProfile), 0644)
+	_ = ioutil.WriteFile(tomlPath, []byte("rate = \"10000\"\n"), 0644)
+
+	yamlLoaded, err := LoadScanProfile(yamlPath)
+	if err != nil {
+		t.Errorf("Expected that error is not returned for yaml profile: %v", err)
+	}
+	jsonLoaded, err := LoadScanProfile(jsonPath)
+	if err != nil {
+		t.Errorf("Expected that error is not returned for json profile: %v", err)
+	}
+	assert.Equal(t, yamlLoaded, jsonLoaded)
+
+	_, err = LoadScanProfile(tomlPath)
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for unsupported extension")
+	}
+}
+
+func TestScanProfile_Options(t *testing.T) {
+	t.Log("Testing Options function of ScanProfile")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	s, err := newScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	profile, err := ParseScanProfileYAML(strings.NewReader(yamlProfile))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while parsing profile: %v", err)
+	}
+	options, err := profile.Options()
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while building options: %v", err)
+	}
+	if err := s.AddOptions(options...); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	assert.Equal(t, []string{"192.168.1.0/24"}, s.targets)
+	assert.Equal(t, "192.168.1.1/32 # gateway\n", s.blocklist.String())
+	assert.Equal(t, []string{
+		"--target-port", "443",
+		"--rate", "10000",
+		"--max-targets", "10%",
+		"--probes", "2",
+		"--dryrun",
+		"--source-port", "40000-40010",
+		"--output-fields", "saddr,sport",
+		"--verbosity", "4",
+	}, s.args.strings())
+}
+
+func TestScanProfile_Options_Errors(t *testing.T) {
+	tests := []struct {
+		testDesc      string
+		profile       *ScanProfile
+		expectedError string
+	}{
+		{
+			testDesc:      "With Wrong Rate",
+			profile:       &ScanProfile{Rate: "fast"},
+			expectedError: "rate: ",
+		},
+		{
+			testDesc:      "With Wrong Bandwidth Unit",
+			profile:       &ScanProfile{Bandwidth: "10T"},
+			expectedError: "bandwidth: ",
+		},
+		{
+			testDesc:      "With Wrong Verbosity",
+			profile:       &ScanProfile{Verbosity: "9"},
+			expectedError: "verbosity: ",
+		},
+		{
+			testDesc:      "With Wrong Blocklist Entry",
+			profile:       &ScanProfile{Blocklist: []string{"10.0.0.0/8", "not-an-ip"}},
+			expectedError: "blocklist: line 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+
+			options, err := test.profile.Options()
+			if err == nil {
+				err = scanner.AddOptions(options...)
+			}
+			t.Logf("Returned Error: %v", err)
+			if err == nil {
+				t.Fatal("Expected that error is returned")
+			}
+			assert.True(t, strings.HasPrefix(err.Error(), test.expectedError))
+		})
+	}
+}
+
+func TestSplitBandwidth(t *testing.T) {
+	t.Log("Testing splitBandwidth function")
+	bandwidth, unit := splitBandwidth("10M")
+	assert.Equal(t, "10", bandwidth)
+	assert.Equal(t, UnitBandwidthMbps, unit)
+
+	bandwidth, unit = splitBandwidth("1000")
+	assert.Equal(t, "1000", bandwidth)
+	assert.Equal(t, UnitBandwidthBps, unit)
+}
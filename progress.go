@@ -0,0 +1,262 @@
+package zmapgo
+
+import (
+	"encoding/csv"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress is a status update of a running scan.
+// Zmap prints one status update per second.
+type Progress struct {
+	// Time is the time of status update.
+	Time time.Time
+	// Elapsed is the time passed since scan started.
+	Elapsed time.Duration
+	// Remaining is the estimated time until scan is finished.
+	Remaining       time.Duration
+	PercentComplete float64
+	// HitRate is the percentage of successful responses to sent packets.
+	HitRate float64
+	// SendDone is true when all packets are sent and zmap is in cooldown.
+	SendDone bool
+
+	Sent        uint64
+	SendRate    float64
+	SendRateAvg float64
+
+	Received    uint64
+	RecvRate    float64
+	RecvRateAvg float64
+
+	// Drops is only available if progress is read from status updates file.
+	Drops       uint64
+	DropRate    float64
+	DropRateAvg float64
+
+	// SendtoFailures is only available if progress is read from status updates file.
+	SendtoFailures uint64
+}
+
+// ProgressHandler is called for every status update while scan is running.
+type ProgressHandler func(progress Progress)
+
+// progressSubscribers holds the progress handlers of a scanner.
+type progressSubscribers struct {
+	mutex    sync.Mutex
+	lastID   int
+	handlers map[int]ProgressHandler
+}
+
+func (p *progressSubscribers) subscribe(handler ProgressHandler) func() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.handlers == nil {
+		p.handlers = map[int]ProgressHandler{}
+	}
+	p.lastID++
+	id := p.lastID
+	p.handlers[id] = handler
+
+	return func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+		delete(p.handlers, id)
+	}
+}
+
+func (p *progressSubscribers) publish(progress Progress) {
+	p.mutex.Lock()
+	handlers := make([]ProgressHandler, 0, len(p.handlers))
+	for _, handler := range p.handlers {
+		handlers = append(handlers, handler)
+	}
+	p.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(progress)
+	}
+}
+
+// OnProgress registers handler to receive status updates while zmap is running.
+// Status updates are read from the file passed with WithStatusUpdatesFile,
+// otherwise from the status lines zmap prints to stderr. WithQuiet disables the latter.
+// The returned function unregisters the handler.
+func (s *scanner) OnProgress(handler ProgressHandler) (unsubscribe func()) {
+	return s.progressSubscribers.subscribe(handler)
+}
+
+var (
+	statusTimeRegexp  = regexp.MustCompile(`^(\S+) ([\d.]+)%(?: \((\S+) left\))?$`)
+	statusSendRegexp  = regexp.MustCompile(`^send: (\d+) (?:done|([\d.]+ ?[KMGT]?)p/s) \(([\d.]+ ?[KMGT]?)p/s avg\)$`)
+	statusRecvRegexp  = regexp.MustCompile(`^recv: (\d+) ([\d.]+ ?[KMGT]?)p/s \(([\d.]+ ?[KMGT]?)p/s avg\)$`)
+	statusDropsRegexp = regexp.MustCompile(`^drops: ([\d.]+ ?[KMGT]?)p/s \(([\d.]+ ?[KMGT]?)p/s avg\)$`)
+)
+
+// parseStatusLine parses a status line that zmap prints to stderr.
+// Ex: " 0:05 12% (40s left); send: 12345 1.23 Kp/s (1.20 Kp/s avg); recv: 123 12 p/s (11 p/s avg); drops: 0 p/s (0 p/s avg); hitrate: 1.00%"
+func parseStatusLine(line string) (Progress, bool) {
+	segments := strings.Split(strings.TrimSpace(line), "; ")
+	if len(segments) < 2 || !strings.HasPrefix(segments[1], "send: ") {
+		return Progress{}, false
+	}
+
+	progress := Progress{Time: time.Now()}
+
+	matches := statusTimeRegexp.FindStringSubmatch(segments[0])
+	if matches == nil {
+		return Progress{}, false
+	}
+	progress.Elapsed = parseStatusDuration(matches[1])
+	progress.PercentComplete, _ = strconv.ParseFloat(matches[2], 64)
+	progress.Remaining = parseStatusDuration(matches[3])
+
+	for _, segment := range segments[1:] {
+		switch {
+		case strings.HasPrefix(segment, "send: "):
+			matches := statusSendRegexp.FindStringSubmatch(segment)
+			if matches == nil {
+				return Progress{}, false
+			}
+			progress.Sent, _ = strconv.ParseUint(matches[1], 10, 64)
+			progress.SendDone = strings.Contains(segment, " done ")
+			progress.SendRate = parseStatusRate(matches[2])
+			progress.SendRateAvg = parseStatusRate(matches[3])
+		case strings.HasPrefix(segment, "recv: "):
+			matches := statusRecvRegexp.FindStringSubmatch(segment)
+			if matches == nil {
+				return Progress{}, false
+			}
+			progress.Received, _ = strconv.ParseUint(matches[1], 10, 64)
+			progress.RecvRate = parseStatusRate(matches[2])
+			progress.RecvRateAvg = parseStatusRate(matches[3])
+		case strings.HasPrefix(segment, "drops: "):
+			matches := statusDropsRegexp.FindStringSubmatch(segment)
+			if matches == nil {
+				return Progress{}, false
+			}
+			progress.DropRate = parseStatusRate(matches[1])
+			progress.DropRateAvg = parseStatusRate(matches[2])
+		case strings.HasPrefix(segment, "hitrate: "):
+			progress.HitRate, _ = strconv.ParseFloat(strings.TrimSuffix(strings.TrimPrefix(segment, "hitrate: "), "%"), 64)
+		}
+	}
+	return progress, true
+}
+
+// parseStatusDuration parses durations in status lines.
+// Elapsed time is printed as "1:02:03" or "2:03", remaining time is printed as "1h02m", "2m03s" or "3s".
+func parseStatusDuration(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if strings.Contains(value, ":") {
+		var total time.Duration
+		for _, part := range strings.Split(value, ":") {
+			number, err := strconv.Atoi(part)
+			if err != nil {
+				return 0
+			}
+			total = total*60 + time.Duration(number)
+		}
+		return total * time.Second
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return duration
+}
+
+// parseStatusRate parses rates in status lines. Ex: "12 ", "1.23 K"
+func parseStatusRate(value string) float64 {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch value[len(value)-1] {
+	case 'K':
+		multiplier = 1e3
+	case 'M':
+		multiplier = 1e6
+	case 'G':
+		multiplier = 1e9
+	case 'T':
+		multiplier = 1e12
+	}
+	if multiplier != 1.0 {
+		value = strings.TrimSpace(value[:len(value)-1])
+	}
+
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return rate * multiplier
+}
+
+// statusUpdatesParser parses lines of the file passed with --status-updates-file.
+type statusUpdatesParser struct {
+	header []string
+}
+
+// parseLine returns false for header line and lines that cannot be parsed.
+func (p *statusUpdatesParser) parseLine(line string) (Progress, bool) {
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return Progress{}, false
+	}
+	if p.header == nil {
+		p.header = record
+		return Progress{}, false
+	}
+
+	values := map[string]string{}
+	for i := range p.header {
+		if i < len(record) {
+			values[p.header[i]] = record[i]
+		}
+	}
+
+	uintValue := func(name string) uint64 {
+		v, _ := strconv.ParseUint(values[name], 10, 64)
+		return v
+	}
+	floatValue := func(name string) float64 {
+		v, _ := strconv.ParseFloat(values[name], 64)
+		return v
+	}
+
+	progress := Progress{
+		Elapsed:         time.Duration(uintValue("time-elapsed")) * time.Second,
+		Remaining:       time.Duration(uintValue("time-remaining")) * time.Second,
+		PercentComplete: floatValue("percent-complete"),
+		HitRate:         floatValue("hit-rate"),
+		SendDone:        values["active-send-threads"] == "0",
+		Sent:            uintValue("sent-total"),
+		SendRate:        floatValue("sent-last-one-sec"),
+		SendRateAvg:     floatValue("sent-avg-per-sec"),
+		Received:        uintValue("recv-success-total"),
+		RecvRate:        floatValue("recv-success-last-one-sec"),
+		RecvRateAvg:     floatValue("recv-success-avg-per-sec"),
+		Drops:           uintValue("pcap-drop-total"),
+		DropRate:        floatValue("drop-last-one-sec"),
+		DropRateAvg:     floatValue("drop-avg-per-sec"),
+		SendtoFailures:  uintValue("sendto-fail-total"),
+	}
+
+	progress.Time, err = time.ParseInLocation("2006-01-02 15:04:05", values["real-time"], time.Local)
+	if err != nil {
+		progress.Time = time.Now()
+	}
+
+	return progress, true
+}
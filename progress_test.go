@@ -0,0 +1,98 @@
+package zmapgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatusLine_Sending(t *testing.T) {
+	t.Log("Testing parseStatusLine function while zmap is sending packets")
+	line := " 0:05 12% (40s left); send: 12345 1.23 Kp/s (1.20 Kp/s avg); recv: 123 12 p/s (11 p/s avg); drops: 0 p/s (0 p/s avg); hitrate: 1.00%"
+
+	progress, ok := parseStatusLine(line)
+	if !assert.True(t, ok, "Expected that status line is parsed") {
+		return
+	}
+
+	assert.Equal(t, 5*time.Second, progress.Elapsed)
+	assert.Equal(t, 40*time.Second, progress.Remaining)
+	assert.Equal(t, 12.0, progress.PercentComplete)
+	assert.False(t, progress.SendDone)
+	assert.Equal(t, uint64(12345), progress.Sent)
+	assert.InDelta(t, 1230.0, progress.SendRate, 0.001)
+	assert.InDelta(t, 1200.0, progress.SendRateAvg, 0.001)
+	assert.Equal(t, uint64(123), progress.Received)
+	assert.Equal(t, 12.0, progress.RecvRate)
+	assert.Equal(t, 11.0, progress.RecvRateAvg)
+	assert.Equal(t, 0.0, progress.DropRate)
+	assert.Equal(t, 1.0, progress.HitRate)
+}
+
+func TestParseStatusLine_SendDone(t *testing.T) {
+	t.Log("Testing parseStatusLine function after zmap sent all packets")
+	line := "1:02:03 100% (2m03s left); send: 256 done (2.34 Kp/s avg); recv: 4 0 p/s (1 p/s avg); drops: 0 p/s (0 p/s avg); hitrate: 1.56%"
+
+	progress, ok := parseStatusLine(line)
+	if !assert.True(t, ok, "Expected that status line is parsed") {
+		return
+	}
+
+	assert.Equal(t, time.Hour+2*time.Minute+3*time.Second, progress.Elapsed)
+	assert.Equal(t, 2*time.Minute+3*time.Second, progress.Remaining)
+	assert.True(t, progress.SendDone)
+	assert.Equal(t, uint64(256), progress.Sent)
+	assert.InDelta(t, 2340.0, progress.SendRateAvg, 0.001)
+	assert.Equal(t, 1.56, progress.HitRate)
+}
+
+func TestParseStatusLine_NotStatusLine(t *testing.T) {
+	t.Log("Testing parseStatusLine function with log line")
+	_, ok := parseStatusLine("Dec 10 12:00:00.001 [INFO] zmap: output module: csv")
+	if ok {
+		t.Error("Expected that log line is not parsed as status line")
+	}
+}
+
+func TestStatusUpdatesParser_NormalBehavior(t *testing.T) {
+	t.Log("Testing statusUpdatesParser under normal behavior")
+	parser := &statusUpdatesParser{}
+
+	_, ok := parser.parseLine("real-time,time-elapsed,time-remaining,percent-complete,hit-rate,active-send-threads,sent-total,sent-last-one-sec,sent-avg-per-sec,recv-success-total,recv-success-last-one-sec,recv-success-avg-per-sec,recv-total,recv-total-last-one-sec,recv-total-avg-per-sec,pcap-drop-total,drop-last-one-sec,drop-avg-per-sec,sendto-fail-total,sendto-fail-last-one-sec,sendto-fail-avg-per-sec")
+	if ok {
+		t.Error("Expected that header line is not returned as progress")
+	}
+
+	progress, ok := parser.parseLine("2021-12-10 12:00:05,5,40,12.500000,1.000000,1,12345,1230,1200,123,12,11,130,13,12,7,0,1,2,0,0")
+	if !assert.True(t, ok, "Expected that status update is parsed") {
+		return
+	}
+
+	assert.Equal(t, time.Date(2021, 12, 10, 12, 0, 5, 0, time.Local), progress.Time)
+	assert.Equal(t, 5*time.Second, progress.Elapsed)
+	assert.Equal(t, 40*time.Second, progress.Remaining)
+	assert.Equal(t, 12.5, progress.PercentComplete)
+	assert.Equal(t, 1.0, progress.HitRate)
+	assert.False(t, progress.SendDone)
+	assert.Equal(t, uint64(12345), progress.Sent)
+	assert.Equal(t, uint64(123), progress.Received)
+	assert.Equal(t, uint64(7), progress.Drops)
+	assert.Equal(t, uint64(2), progress.SendtoFailures)
+}
+
+func TestOnProgress_Unsubscribe(t *testing.T) {
+	t.Log("Testing OnProgress function with unsubscribing")
+	s := &scanner{}
+
+	count := 0
+	unsubscribe := s.OnProgress(func(progress Progress) {
+		count++
+	})
+
+	s.progressSubscribers.publish(Progress{})
+	unsubscribe()
+	s.progressSubscribers.publish(Progress{})
+
+	assert.Equal(t, 1, count)
+}
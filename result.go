@@ -0,0 +1,214 @@
+package zmapgo
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is the typed form of a zmap result row.
+// Fields which are not requested with --output-fields keep their zero value.
+type Result struct {
+	Saddr          net.IP
+	SaddrRaw       uint32
+	Daddr          net.IP
+	DaddrRaw       uint32
+	IPID           uint16
+	TTL            uint8
+	Sport          uint16
+	Dport          uint16
+	Seqnum         uint32
+	Acknum         uint32
+	Window         uint16
+	Classification string
+	Success        bool
+	Repeat         bool
+	Cooldown       bool
+	Timestamp      time.Time
+
+	// Extras holds the fields that have no dedicated field in Result.
+	// Values are coerced according to the output field type (int64, bool, string or []byte).
+	Extras map[string]interface{}
+}
+
+// ParseResult converts a result row into Result.
+// Values are coerced using the type informations in fields, which can be taken from ListOutputFields.
+// Fields that are not in fields are treated as string.
+func ParseResult(row map[string]interface{}, fields []OutputField) (*Result, error) {
+	fieldTypes := map[string]string{}
+	for _, field := range fields {
+		fieldTypes[field.Name] = field.Type
+	}
+
+	result := &Result{}
+	var timestampTs, timestampUs int64
+	var timestampTsPassed bool
+
+	for name, rawValue := range row {
+		strValue, ok := rawValue.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of %s field is not a string", name)
+		}
+		if strValue == "" {
+			continue
+		}
+
+		fieldType, ok := fieldTypes[name]
+		if !ok {
+			fieldType = OutputFieldTypeString
+		}
+
+		value, err := coerceFieldValue(strValue, fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse value of %s field: %w", name, err)
+		}
+
+		switch name {
+		case "saddr":
+			result.Saddr = net.ParseIP(strValue)
+		case "saddr_raw":
+			result.SaddrRaw = uint32(toInt64(value))
+		case "daddr":
+			result.Daddr = net.ParseIP(strValue)
+		case "daddr_raw":
+			result.DaddrRaw = uint32(toInt64(value))
+		case "ipid":
+			result.IPID = uint16(toInt64(value))
+		case "ttl":
+			result.TTL = uint8(toInt64(value))
+		case "sport":
+			result.Sport = uint16(toInt64(value))
+		case "dport":
+			result.Dport = uint16(toInt64(value))
+		case "seqnum":
+			result.Seqnum = uint32(toInt64(value))
+		case "acknum":
+			result.Acknum = uint32(toInt64(value))
+		case "window":
+			result.Window = uint16(toInt64(value))
+		case "classification":
+			result.Classification = strValue
+		case "success":
+			result.Success = toBool(value)
+		case "repeat":
+			result.Repeat = toBool(value)
+		case "cooldown":
+			result.Cooldown = toBool(value)
+		case "timestamp_str":
+			timestamp, err := parseResultTimestamp(strValue)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse value of %s field: %w", name, err)
+			}
+			result.Timestamp = timestamp
+		case "timestamp_ts":
+			timestampTs = toInt64(value)
+			timestampTsPassed = true
+		case "timestamp_us":
+			timestampUs = toInt64(value)
+		default:
+			if result.Extras == nil {
+				result.Extras = map[string]interface{}{}
+			}
+			result.Extras[name] = value
+		}
+	}
+
+	// timestamp_str is more precise than timestamp_ts. So it is used if both passed.
+	if result.Timestamp.IsZero() && timestampTsPassed {
+		result.Timestamp = time.Unix(timestampTs, timestampUs*int64(time.Microsecond))
+	}
+
+	return result, nil
+}
+
+// ParseResults converts all result rows into Result.
+func ParseResults(rows []map[string]interface{}, fields []OutputField) ([]Result, error) {
+	results := make([]Result, 0, len(rows))
+	for _, row := range rows {
+		result, err := ParseResult(row, fields)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return results, nil
+}
+
+// TypedResultHandler returns a ResultHandler that converts every row into Result before passing it to handler.
+// It can be used with RunStream.
+func TypedResultHandler(fields []OutputField, handler func(result *Result) error) ResultHandler {
+	return func(row map[string]interface{}) error {
+		result, err := ParseResult(row, fields)
+		if err != nil {
+			return err
+		}
+		return handler(result)
+	}
+}
+
+// coerceFieldValue converts value to the go type of fieldType.
+func coerceFieldValue(value string, fieldType string) (interface{}, error) {
+	switch strings.TrimSuffix(fieldType, ":") {
+	case OutputFieldTypeInt:
+		intValue, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			// Some fields like seqnum can exceed int64 on custom builds.
+			uintValue, uErr := strconv.ParseUint(value, 10, 64)
+			if uErr != nil {
+				return nil, err
+			}
+			return int64(uintValue), nil
+		}
+		return intValue, nil
+	case OutputFieldTypeBool:
+		return strconv.ParseBool(value)
+	case OutputFieldTypeBinary:
+		return hex.DecodeString(value)
+	default:
+		return value, nil
+	}
+}
+
+// parseResultTimestamp parses timestamp_str field. Zmap uses ISO8601 format with milliseconds.
+func parseResultTimestamp(value string) (time.Time, error) {
+	layouts := []string{
+		"2006-01-02T15:04:05.000-0700",
+		"2006-01-02T15:04:05-0700",
+		time.RFC3339Nano,
+	}
+
+	var err error
+	for _, layout := range layouts {
+		var timestamp time.Time
+		timestamp, err = time.Parse(layout, value)
+		if err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+func toInt64(value interface{}) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case string:
+		i, _ := strconv.ParseInt(v, 10, 64)
+		return i
+	}
+	return 0
+}
+
+func toBool(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package zmapgo
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testOutputFields = []OutputField{
+	{Name: "saddr", Type: "string"},
+	{Name: "saddr_raw", Type: "int"},
+	{Name: "sport", Type: "int"},
+	{Name: "dport", Type: "int"},
+	{Name: "seqnum", Type: "int"},
+	{Name: "ttl", Type: "int"},
+	{Name: "classification", Type: "string"},
+	{Name: "success", Type: "bool"},
+	{Name: "repeat", Type: "bool"},
+	{Name: "timestamp_str", Type: "string"},
+	{Name: "timestamp_ts", Type: "int"},
+	{Name: "timestamp_us", Type: "int"},
+	{Name: "icmp_type", Type: "int"},
+	{Name: "data", Type: "binary"},
+}
+
+func TestParseResult_NormalBehavior(t *testing.T) {
+	t.Log("Testing ParseResult function under normal behavior")
+	row := map[string]interface{}{
+		"saddr":          "1.1.1.1",
+		"saddr_raw":      "16843009",
+		"sport":          "80",
+		"dport":          "54321",
+		"seqnum":         "3423748349",
+		"ttl":            "57",
+		"classification": "synack",
+		"success":        "1",
+		"repeat":         "false",
+		"timestamp_str":  "2021-12-10T12:30:45.123+0300",
+	}
+
+	result, err := ParseResult(row, testOutputFields)
+	t.Logf("Returned Error: %v", err)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, net.ParseIP("1.1.1.1").Equal(result.Saddr))
+	assert.Equal(t, uint32(16843009), result.SaddrRaw)
+	assert.Equal(t, uint16(80), result.Sport)
+	assert.Equal(t, uint16(54321), result.Dport)
+	assert.Equal(t, uint32(3423748349), result.Seqnum)
+	assert.Equal(t, uint8(57), result.TTL)
+	assert.Equal(t, "synack", result.Classification)
+	assert.True(t, result.Success)
+	assert.False(t, result.Repeat)
+	assert.Equal(t, time.Date(2021, 12, 10, 9, 30, 45, 123000000, time.UTC), result.Timestamp.UTC())
+	assert.Nil(t, result.Extras)
+}
+
+func TestParseResult_TimestampFromEpoch(t *testing.T) {
+	t.Log("Testing ParseResult function with timestamp_ts and timestamp_us fields")
+	row := map[string]interface{}{
+		"timestamp_ts": "1639139445",
+		"timestamp_us": "123456",
+	}
+
+	result, err := ParseResult(row, testOutputFields)
+	t.Logf("Returned Error: %v", err)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, time.Unix(1639139445, 123456000), result.Timestamp)
+}
+
+func TestParseResult_Extras(t *testing.T) {
+	t.Log("Testing ParseResult function with fields that have no dedicated field in Result")
+	row := map[string]interface{}{
+		"icmp_type": "0",
+		"data":      "48656c6c6f",
+		"unknown":   "value",
+	}
+
+	result, err := ParseResult(row, testOutputFields)
+	t.Logf("Returned Error: %v", err)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, map[string]interface{}{
+		"icmp_type": int64(0),
+		"data":      []byte("Hello"),
+		"unknown":   "value",
+	}, result.Extras)
+}
+
+func TestParseResult_WrongValue(t *testing.T) {
+	t.Log("Testing ParseResult function with value that does not match the field type")
+	row := map[string]interface{}{
+		"sport": "not-a-port",
+	}
+
+	_, err := ParseResult(row, testOutputFields)
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when value does not match the field type")
+	}
+}
+
+func TestTypedResultHandler_NormalBehavior(t *testing.T) {
+	t.Log("Testing TypedResultHandler function under normal behavior")
+	var results []*Result
+	handler := TypedResultHandler(testOutputFields, func(result *Result) error {
+		results = append(results, result)
+		return nil
+	})
+
+	err := handler(map[string]interface{}{"saddr": "1.1.1.1", "sport": "80"})
+	t.Logf("Returned Error: %v", err)
+	if !assert.NoError(t, err) || !assert.Len(t, results, 1) {
+		return
+	}
+	assert.Equal(t, uint16(80), results[0].Sport)
+
+	err = handler(map[string]interface{}{"sport": "wrong"})
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned when row cannot be parsed")
+	}
+	if errors.Unwrap(err) == nil {
+		t.Error("Expected that parse error is wrapped")
+	}
+}
@@ -0,0 +1,140 @@
+package zmapgo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// Command describes a zmap process that a Runner starts.
+type Command struct {
+	// Path is the zmap binary path.
+	Path string
+	// Args are the zmap arguments, not including the binary path.
+	Args []string
+	// Stdout and Stderr receive the output of zmap. Output is discarded if they are nil.
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Process is a zmap process started by a Runner.
+type Process interface {
+	// Wait waits for the process to exit and for its output to be copied to Stdout and Stderr.
+	// The returned error should have an "ExitCode() int" method, like *exec.ExitError, if the process exited with a non zero code.
+	Wait() error
+	// Signal sends a signal to the process.
+	Signal(sig os.Signal) error
+	// Kill kills the process immediately.
+	Kill() error
+}
+
+// Runner controls how zmap processes are started.
+// It can be used to run zmap with sudo, inside a network namespace, in a container or on another host.
+type Runner interface {
+	Start(ctx context.Context, command *Command) (Process, error)
+}
+
+// execRunner starts zmap processes on the local host with os/exec.
+type execRunner struct {
+	prefix []string
+}
+
+// NewExecRunner returns the default Runner, which starts zmap on the local host.
+func NewExecRunner() Runner {
+	return &execRunner{}
+}
+
+// NewPrefixRunner returns a Runner that starts zmap with the given command prefix.
+// Ex: NewPrefixRunner("sudo", "-n"), NewPrefixRunner("ip", "netns", "exec", "scan"), NewPrefixRunner("docker", "exec", "-i", "zmap")
+//
+// Files passed to zmap, like output and log files, are read by zmapgo on the local host.
+// If the prefix runs zmap on another host or in a container, they must be on a shared filesystem.
+//
+// Zmapgo does not create temporary files for scanners with a custom runner, since zmap may not see them.
+// Metadata is only parsed if WithMetadataFile is passed, all targets are passed as arguments,
+// and WithBlocklist and WithAllowlist cannot be used.
+func NewPrefixRunner(prefix ...string) Runner {
+	return &execRunner{prefix: prefix}
+}
+
+func (r *execRunner) Start(ctx context.Context, command *Command) (Process, error) {
+	name := command.Path
+	args := command.Args
+	if len(r.prefix) > 0 {
+		name = r.prefix[0]
+		args = append(append(append([]string{}, r.prefix[1:]...), command.Path), command.Args...)
+	}
+
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = command.Stdout
+	cmd.Stderr = command.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return &execProcess{cmd: cmd}, nil
+}
+
+// execProcess is a Process started with os/exec.
+type execProcess struct {
+	cmd *exec.Cmd
+}
+
+func (p *execProcess) Wait() error {
+	return p.cmd.Wait()
+}
+
+func (p *execProcess) Signal(sig os.Signal) error {
+	return p.cmd.Process.Signal(sig)
+}
+
+func (p *execProcess) Kill() error {
+	return p.cmd.Process.Kill()
+}
+
+// exitCode returns the exit code of a process from the error returned by Wait.
+// It returns -1 if the exit code is unknown, like when the process is killed.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if exitErr, ok := err.(interface{ ExitCode() int }); ok {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// output starts zmap with the given arguments using the runner of the scanner and returns its stdout.
+func (s *scanner) output(ctx context.Context, args ...string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var stdout bytes.Buffer
+	process, err := s.runner.Start(ctx, &Command{
+		Path:   s.binaryPath,
+		Args:   args,
+		Stdout: &stdout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Runners may ignore ctx, so the process is killed here when ctx is done.
+	done := make(chan error, 1)
+	go func() {
+		done <- process.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return stdout.Bytes(), err
+		}
+		return stdout.Bytes(), nil
+	case <-ctx.Done():
+		_ = process.Kill()
+		<-done
+		return nil, ctx.Err()
+	}
+}
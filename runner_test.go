@@ -0,0 +1,251 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryRunner is an in-memory Runner that writes canned output instead of starting a process.
+type memoryRunner struct {
+	outputs  map[string]string
+	commands []*Command
+}
+
+func (r *memoryRunner) Start(ctx context.Context, command *Command) (Process, error) {
+	r.commands = append(r.commands, command)
+	output := r.outputs["scan"]
+	if len(command.Args) == 1 {
+		if o, ok := r.outputs[command.Args[0]]; ok {
+			output = o
+		}
+	}
+
+	process := &memoryProcess{done: make(chan struct{})}
+	go func() {
+		defer close(process.done)
+		if command.Stdout != nil {
+			_, _ = io.WriteString(command.Stdout, output)
+		}
+	}()
+	return process, nil
+}
+
+type memoryProcess struct {
+	done chan struct{}
+}
+
+func (p *memoryProcess) Wait() error {
+	<-p.done
+	return nil
+}
+
+func (p *memoryProcess) Signal(sig os.Signal) error {
+	return nil
+}
+
+func (p *memoryProcess) Kill() error {
+	return nil
+}
+
+func TestWithRunner_MemoryRunner(t *testing.T) {
+	t.Log("Testing scanner with an in-memory runner")
+	runner := &memoryRunner{outputs: map[string]string{
+		"--version":            "zmap 2.1.1\n",
+		"--list-probe-modules": "tcp_synscan\nicmp_echoscan\n",
+		"--list-output-fields": "saddr           string: source IP address of response\n",
+		"scan":                 "1.1.1.1\n",
+	}}
+
+	scanner, err := NewBlockingScanner(WithRunner(runner), WithBinaryPath("/remote/zmap"))
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned while creating scanner with a custom runner")
+	}
+
+	version, err := scanner.GetVersion()
+	if err != nil {
+		t.Error("Expected that error is not returned while getting version")
+	}
+	assert.Equal(t, "2.1.1", version)
+
+	probeModules, err := scanner.ListProbeModules()
+	if err != nil {
+		t.Error("Expected that error is not returned while listing probe modules")
+	}
+	assert.Equal(t, []string{"tcp_synscan", "icmp_echoscan"}, probeModules)
+
+	if err := scanner.AddOptions(WithTargetPort("80"), WithOutputFields([]string{"saddr"})); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned while running scan with a custom runner")
+	}
+	if assert.NotNil(t, result) {
+		assert.Equal(t, []map[string]interface{}{{"saddr": "1.1.1.1"}}, result.Results)
+		assert.Equal(t, 0, result.ExitCode)
+	}
+
+	for _, command := range runner.commands {
+		assert.Equal(t, "/remote/zmap", command.Path)
+	}
+}
+
+func TestWithRunner_TempFiles(t *testing.T) {
+	t.Log("Testing scanner with a custom runner does not pass local temporary files to zmap")
+	runner := &memoryRunner{outputs: map[string]string{
+		"--version":            "zmap 2.1.1\n",
+		"--list-output-fields": "saddr           string: source IP address of response\n",
+		"scan":                 "saddr\n1.1.1.1\n",
+	}}
+	scanner, err := NewBlockingScanner(WithRunner(runner), WithBinaryPath("/remote/zmap"))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with a custom runner: %v", err)
+	}
+
+	var targets []string
+	for i := 0; i < maxTargetArgs+1; i++ {
+		targets = append(targets, fmt.Sprintf("10.0.%d.%d", i/256, i%256))
+	}
+	if err := scanner.AddOptions(WithTargetPort("80"), WithTargets(targets...)); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned while running scan with a custom runner")
+	}
+	assert.Nil(t, result.Metadata, "Expected that metadata is not parsed without metadata file")
+
+	args := runner.commands[len(runner.commands)-1].Args
+	_, metadataFilePassed := getArgumentValue(args, "--metadata-file")
+	assert.False(t, metadataFilePassed, "Expected that temporary metadata file is not passed")
+	_, whitelistFilePassed := getArgumentValue(args, "--whitelist-file")
+	assert.False(t, whitelistFilePassed, "Expected that temporary targets file is not passed")
+	assert.Equal(t, targets, args[len(args)-len(targets):])
+
+	blocklist := NewBlocklist()
+	if err := blocklist.Add("10.0.0.0/24", ""); err != nil {
+		t.Fatalf("Expected that error is not returned while adding network to blocklist: %v", err)
+	}
+	if err := scanner.AddOptions(WithBlocklist(blocklist)); err != nil {
+		t.Fatalf("Expected that error is not returned while adding blocklist: %v", err)
+	}
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for blocklist with a custom runner")
+	}
+}
+
+func TestNewPrefixRunner(t *testing.T) {
+	t.Log("Testing NewPrefixRunner function with env as prefix")
+	binary := zmaptest.New(t, zmaptest.Config{Results: "saddr\n1.1.1.1\n"})
+
+	scanner, err := NewBlockingScanner(WithRunner(NewPrefixRunner("env", "ZMAPGO_TEST=1")), WithBinaryPath(binary.Path))
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned while creating scanner with prefix runner")
+	}
+	if err := scanner.AddOptions(WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned while running scan with prefix runner")
+	}
+	if assert.NotNil(t, result) {
+		assert.Len(t, result.Results, 1)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Log("Testing exitCode function")
+	assert.Equal(t, 0, exitCode(nil))
+	assert.Equal(t, -1, exitCode(errors.New("killed")))
+
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	assert.Equal(t, 3, exitCode(err))
+}
+
+// hangingRunner starts processes that never exit until they are killed.
+type hangingRunner struct {
+	killed chan struct{}
+}
+
+func (r *hangingRunner) Start(ctx context.Context, command *Command) (Process, error) {
+	return &hangingProcess{killed: r.killed}, nil
+}
+
+type hangingProcess struct {
+	killed chan struct{}
+}
+
+func (p *hangingProcess) Wait() error {
+	<-p.killed
+	return errors.New("signal: killed")
+}
+
+func (p *hangingProcess) Signal(sig os.Signal) error {
+	return nil
+}
+
+func (p *hangingProcess) Kill() error {
+	close(p.killed)
+	return nil
+}
+
+func TestListOutputFieldsContext_Cancel(t *testing.T) {
+	t.Log("Testing ListOutputFieldsContext function kills zmap when the context is done")
+	runner := &hangingRunner{killed: make(chan struct{})}
+	scanner, err := NewBlockingScanner(WithRunner(runner))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with runner: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = scanner.ListOutputFieldsContext(ctx)
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Expected that context error is returned")
+	}
+
+	select {
+	case <-runner.killed:
+	default:
+		t.Error("Expected that zmap is killed")
+	}
+}
+
+func TestListProbeModulesContext_ScannerContext(t *testing.T) {
+	t.Log("Testing ListProbeModulesContext function is stopped by the context of the scanner too")
+	scannerCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	binary := zmaptest.New(t, zmaptest.Config{})
+	s, err := newScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	// Context of the scanner is done after the binary is checked.
+	s.ctx = scannerCtx
+
+	_, err = s.ListProbeModulesContext(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, context.Canceled) {
+		t.Error("Expected that context error is returned")
+	}
+}
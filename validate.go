@@ -0,0 +1,205 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationErrors holds every violation found by Validate.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	if len(e) == 1 {
+		return "invalid zmap options: " + messages[0]
+	}
+	return fmt.Sprintf("%d invalid zmap options: %s", len(e), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the violations. errors.Is and errors.As use it since Go 1.20.
+func (e ValidationErrors) Unwrap() []error {
+	return e
+}
+
+// Is reports whether any of the violations matches target, so that errors.Is works before Go 1.20 as well.
+func (e ValidationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first violation that matches target, so that errors.As works before Go 1.20 as well.
+func (e ValidationErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultProbeModule is used by zmap if --probe-module is not passed.
+const defaultProbeModule = "tcp_synscan"
+
+// portProbeModules are the zmap probe modules that send packets to a target port.
+var portProbeModules = []string{
+	"tcp_synscan",
+	"tcp_synackscan",
+	"udp",
+	"ntp",
+	"upnp",
+	"dns",
+	"bacnet",
+	ProbeModuleIPv6TCPSynScan,
+	ProbeModuleIPv6UDP,
+	ProbeModuleIPv6UDPDNS,
+}
+
+// probeModuleNeedsPort returns true if probeModule sends packets to a target port.
+func probeModuleNeedsPort(probeModule string) bool {
+	return containsString(portProbeModules, probeModule)
+}
+
+// outputFilterFieldRegexp matches the field names on the left side of comparisons. Ex: "success = 1"
+var outputFilterFieldRegexp = regexp.MustCompile(`([A-Za-z_][A-Za-z0-9_]*)\s*(?:!=|<=|>=|=|<|>)`)
+
+// Validate checks the combination of options and returns every violation at once as ValidationErrors.
+// Each option is already validated while it is added. Validate checks the rules between options.
+// It is called before every run.
+func (s *scanner) Validate() error {
+	return s.ValidateContext(s.ctx)
+}
+
+// ValidateContext is Validate with a context for the zmap commands run to list output fields.
+func (s *scanner) ValidateContext(ctx context.Context) error {
+	var violations ValidationErrors
+
+	// Short flags and the config file are taken into account, as zmap does.
+	args := s.effectiveArguments()
+
+	probeModule, ok := args.value("--probe-module")
+	if !ok {
+		probeModule = defaultProbeModule
+	}
+
+	// Target port is required for tcp and udp probes
+	_, targetPortPassed := args.value("--target-port")
+	_, targetPortsPassed := args.value("--target-ports")
+	if probeModuleNeedsPort(probeModule) && !targetPortPassed && !targetPortsPassed {
+		violations = append(violations, fmt.Errorf("target port is required for %s probe module", probeModule))
+	}
+
+	// Shard must be less than shards
+	shard, shardPassed := args.value("--shard")
+	shards, shardsPassed := args.value("--shards")
+	if shardPassed || shardsPassed {
+		shardValue, totalShardsValue := 0, 1
+		if shardPassed {
+			shardValue, _ = strconv.Atoi(shard)
+		}
+		if shardsPassed {
+			totalShardsValue, _ = strconv.Atoi(shards)
+		}
+		if shardValue >= totalShardsValue {
+			violations = append(violations, fmt.Errorf("shard %d must be less than total shards %d", shardValue, totalShardsValue))
+		}
+	}
+
+	// Rate and bandwidth conflict
+	_, ratePassed := args.value("--rate")
+	_, bandwidthPassed := args.value("--bandwidth")
+	if ratePassed && bandwidthPassed {
+		violations = append(violations, errors.New("rate and bandwidth cannot be used together"))
+	}
+
+	// Source port range must have a port for every probe
+	if probes, ok := args.value("--probes"); ok {
+		probesValue, _ := strconv.Atoi(probes)
+		if sourcePort, ok := args.value("--source-port"); ok {
+			if portCount := sourcePortCount(sourcePort); portCount < probesValue {
+				violations = append(violations, fmt.Errorf("source port range has %d ports, but %d probes are sent to every target", portCount, probesValue))
+			}
+		}
+	}
+
+	// IPv6 targets need ipv6 source ip and ipv6 probe module
+	if _, ok := args.value("--ipv6-target-file"); ok || len(s.ipv6Targets) > 0 {
+		if _, ok := args.value("--ipv6-source-ip"); !ok {
+			violations = append(violations, errors.New("ipv6 source ip is required for ipv6 targets"))
+		}
+		if !isIPv6ProbeModule(probeModule) {
+			violations = append(violations, fmt.Errorf("ipv6 targets cannot be scanned with %s probe module", probeModule))
+		}
+	}
+
+	// Output fields and output filter may only reference fields of the probe module
+	var outputFields []string
+	if index := args.index("--output-fields"); index >= 0 {
+		outputFields = args[index].List()
+	}
+	outputFilter, outputFilterPassed := args.value("--output-filter")
+	if len(outputFields) > 0 || outputFilterPassed {
+		probeModuleArgument, _ := args.value("--probe-module")
+		availableFields, err := s.ListOutputFieldsForContext(ctx, probeModuleArgument)
+		if err != nil {
+			violations = append(violations, fmt.Errorf("cannot list output fields of %s probe module: %w", probeModule, err))
+		} else {
+			for _, field := range outputFields {
+				if !outputFieldExists(availableFields, field) {
+					violations = append(violations, fmt.Errorf("output field %s is not available for %s probe module", field, probeModule))
+				}
+			}
+			for _, field := range outputFilterFields(outputFilter) {
+				if !outputFieldExists(availableFields, field) {
+					violations = append(violations, fmt.Errorf("output filter references unknown field %s", field))
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return violations
+	}
+	return nil
+}
+
+// sourcePortCount returns the number of ports in a source port or source port range.
+func sourcePortCount(sourcePort string) int {
+	if !strings.Contains(sourcePort, "-") {
+		return 1
+	}
+	splitted := strings.Split(sourcePort, "-")
+	lower, _ := strconv.Atoi(splitted[0])
+	greater, _ := strconv.Atoi(splitted[len(splitted)-1])
+	return greater - lower + 1
+}
+
+// outputFilterFields returns the field names referenced in an output filter. Ex: "success = 1 && repeat = 0"
+func outputFilterFields(outputFilter string) []string {
+	var fields []string
+	for _, matches := range outputFilterFieldRegexp.FindAllStringSubmatch(outputFilter, -1) {
+		if !containsString(fields, matches[1]) {
+			fields = append(fields, matches[1])
+		}
+	}
+	return fields
+}
+
+func outputFieldExists(fields []OutputField, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package zmapgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		testDesc           string
+		args               []string
+		ipv6Targets        []string
+		expectedViolations int
+	}{
+		{
+			testDesc:           "With Valid Options",
+			args:               []string{"--target-port", "80", "--rate", "10000", "--shard", "1", "--shards", "2", "--output-filter", "success = 1 && repeat = 0"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "Without Target Port For Default Probe Module",
+			args:               []string{"--rate", "10000"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "Without Target Port For ICMP Probe Module",
+			args:               []string{"--probe-module", "icmp_echoscan"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Short Target Port Flag",
+			args:               []string{"-p", "80"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Short Probe Module Flag",
+			args:               []string{"-M", "icmp_echoscan"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "Without Target Port For Unknown Probe Module",
+			args:               []string{"--probe-module", "custom_tcp_module"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Target Ports",
+			args:               []string{"--target-ports", "80,443"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Shard Greater Than Shards",
+			args:               []string{"--target-port", "80", "--shard", "2", "--shards", "2"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "With Shard Without Shards",
+			args:               []string{"--target-port", "80", "--shard", "1"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "With Rate And Bandwidth",
+			args:               []string{"--target-port", "80", "--rate", "10000", "--bandwidth", "10M"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "With Not Enough Source Ports For Probes",
+			args:               []string{"--target-port", "80", "--source-port", "40000-40001", "--probes", "3"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "With Enough Source Ports For Probes",
+			args:               []string{"--target-port", "80", "--source-port", "40000-40002", "--probes", "3"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Unknown Output Filter Field",
+			args:               []string{"--target-port", "80", "--output-filter", "success = 1 && unknown_field > 2"},
+			expectedViolations: 1,
+		},
+		{
+			testDesc:           "With IPv6 Target File Without Source IP And Probe Module",
+			args:               []string{"--target-port", "80", "--ipv6-target-file", "targets.txt"},
+			expectedViolations: 2,
+		},
+		{
+			testDesc:           "With IPv6 Targets Without Source IP And Probe Module",
+			ipv6Targets:        []string{"2001:db8::1"},
+			args:               []string{"--target-port", "80"},
+			expectedViolations: 2,
+		},
+		{
+			testDesc:           "With Every Violation",
+			args:               []string{"--shard", "3", "--shards", "2", "--rate", "1", "--bandwidth", "1G", "--source-port", "40000", "--probes", "2", "--output-filter", "unknown = 1"},
+			expectedViolations: 5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{})
+			s, err := newScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			s.args = parseArguments(test.args)
+			s.ipv6Targets = test.ipv6Targets
+
+			err = s.Validate()
+			t.Logf("Returned Error: %v", err)
+			if test.expectedViolations == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var violations ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatal("Expected that returned error is ValidationErrors")
+			}
+			assert.Len(t, violations, test.expectedViolations)
+		})
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	t.Log("Testing Run function validates options before launching zmap")
+	binary := zmaptest.New(t, zmaptest.Config{})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	invocations := len(binary.Invocations())
+
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+
+	var violations ValidationErrors
+	if !errors.As(err, &violations) {
+		t.Error("Expected that ValidationErrors is returned")
+	}
+	assert.Len(t, binary.Invocations(), invocations, "Expected that zmap is not launched")
+}
+
+func TestValidate_ConfigFile(t *testing.T) {
+	tests := []struct {
+		testDesc           string
+		config             string
+		options            []Option
+		expectedViolations int
+	}{
+		{
+			testDesc:           "With Target Port In Config File",
+			config:             "target-port 80\n",
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Probe Module In Config File",
+			config:             "probe-module icmp_echoscan\n",
+			options:            []Option{WithOutputFields([]string{"saddr", "type"})},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Probe Module In Config File Overridden",
+			config:             "probe-module icmp_echoscan\n",
+			options:            []Option{WithProbeModule("tcp_synscan")},
+			expectedViolations: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{ModuleOutputFields: icmpOutputFields})
+			configFile := filepath.Join(t.TempDir(), "zmap.conf")
+			if err := ioutil.WriteFile(configFile, []byte(test.config), 0644); err != nil {
+				t.Fatalf("Expected that config file is written: %v", err)
+			}
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			if err := scanner.AddOptions(append([]Option{WithConfigFile(configFile)}, test.options...)...); err != nil {
+				t.Fatalf("Expected that error is not returned while adding options: %v", err)
+			}
+
+			err = scanner.Validate()
+			t.Logf("Returned Error: %v", err)
+			if test.expectedViolations == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var violations ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatal("Expected that returned error is ValidationErrors")
+			}
+			assert.Len(t, violations, test.expectedViolations)
+		})
+	}
+}
+
+func TestOutputFilterFields(t *testing.T) {
+	t.Log("Testing outputFilterFields function")
+	fields := outputFilterFields("(classification = rst || success != 0) && ttl>=64 && success = 1")
+	assert.Equal(t, []string{"classification", "success", "ttl"}, fields)
+}
+
+func TestValidate_ProbeModuleOutputFields(t *testing.T) {
+	tests := []struct {
+		testDesc           string
+		args               []string
+		expectedViolations int
+	}{
+		{
+			testDesc:           "With Fields Of Probe Module",
+			args:               []string{"--probe-module", "icmp_echoscan", "--output-fields", "saddr,type,code", "--output-filter", "type = 0"},
+			expectedViolations: 0,
+		},
+		{
+			testDesc:           "With Fields Of Default Probe Module",
+			args:               []string{"--probe-module", "icmp_echoscan", "--output-fields", "saddr,sport", "--output-filter", "classification = rst"},
+			expectedViolations: 2,
+		},
+		{
+			testDesc:           "With Fields Of Other Probe Module",
+			args:               []string{"--target-port", "80", "--output-fields", "saddr,type"},
+			expectedViolations: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{ModuleOutputFields: icmpOutputFields})
+			s, err := newScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			s.args = parseArguments(test.args)
+
+			err = s.Validate()
+			t.Logf("Returned Error: %v", err)
+			if test.expectedViolations == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var violations ValidationErrors
+			if !errors.As(err, &violations) {
+				t.Fatal("Expected that returned error is ValidationErrors")
+			}
+			assert.Len(t, violations, test.expectedViolations)
+		})
+	}
+}
+
+func TestValidationErrors_IsAs(t *testing.T) {
+	t.Log("Testing errors.Is and errors.As match the violations of ValidationErrors")
+	exitErr := &ZmapExitError{ExitCode: 1}
+	err := fmt.Errorf("validate: %w", ValidationErrors{
+		errors.New("rate and bandwidth cannot be used together"),
+		fmt.Errorf("cannot list output fields: %w", ErrMissingCapNetRaw),
+		exitErr,
+	})
+
+	assert.True(t, errors.Is(err, ErrMissingCapNetRaw), "Expected that errors.Is matches a wrapped violation")
+	assert.False(t, errors.Is(err, ErrUnknownInterface), "Expected that errors.Is does not match a missing violation")
+
+	var target *ZmapExitError
+	if assert.True(t, errors.As(err, &target), "Expected that errors.As matches a violation") {
+		assert.Same(t, exitErr, target)
+	}
+
+	var violations ValidationErrors
+	if assert.True(t, errors.As(err, &violations), "Expected that errors.As matches ValidationErrors itself") {
+		assert.Len(t, violations, 3)
+	}
+}
@@ -0,0 +1,150 @@
+package zmapgo
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ZmapVersion is a parsed zmap version. Ex: "2.1.1", "4.1.0-RC1"
+type ZmapVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// PreRelease is the part after the patch number. Ex: "RC1"
+	PreRelease string
+}
+
+var versionRegexp = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?(?:[-~+]?([0-9A-Za-z.]+))?`)
+
+// ParseZmapVersion parses the output of `zmap --version` or a version string.
+// Development builds don't print a version, so an error is returned for them.
+func ParseZmapVersion(version string) (ZmapVersion, error) {
+	matches := versionRegexp.FindStringSubmatch(version)
+	if matches == nil {
+		return ZmapVersion{}, fmt.Errorf("cannot parse zmap version from %q", version)
+	}
+
+	var parsed ZmapVersion
+	parsed.Major, _ = strconv.Atoi(matches[1])
+	parsed.Minor, _ = strconv.Atoi(matches[2])
+	if matches[3] != "" {
+		parsed.Patch, _ = strconv.Atoi(matches[3])
+	}
+	parsed.PreRelease = matches[4]
+	return parsed, nil
+}
+
+func (v ZmapVersion) String() string {
+	version := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.PreRelease != "" {
+		version += "-" + v.PreRelease
+	}
+	return version
+}
+
+// AtLeast returns true if v is equal to or newer than other.
+// Pre-releases are considered older than the release. Ex: "4.0.0-RC1" is older than "4.0.0"
+func (v ZmapVersion) AtLeast(other ZmapVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	if v.Patch != other.Patch {
+		return v.Patch > other.Patch
+	}
+	if v.PreRelease == other.PreRelease {
+		return true
+	}
+	if v.PreRelease == "" {
+		return true
+	}
+	if other.PreRelease == "" {
+		return false
+	}
+	return v.PreRelease > other.PreRelease
+}
+
+// Capabilities is what the zmap binary of a scanner supports.
+type Capabilities struct {
+	Version ZmapVersion
+	// VersionKnown is false for development builds. All features are assumed to be supported for them.
+	VersionKnown bool
+	Features     map[Feature]bool
+
+	ProbeModules  []string
+	OutputModules []string
+	// OutputFields are the output fields of the probe module of the scanner. See ListOutputFieldsFor.
+	OutputFields []OutputField
+}
+
+// Has returns true if feature is supported.
+func (c *Capabilities) Has(feature Feature) bool {
+	return c.Features[feature]
+}
+
+// newCapabilities returns the version and the features of the given `zmap --version` output.
+func newCapabilities(version string) *Capabilities {
+	capabilities := &Capabilities{Features: map[Feature]bool{}}
+
+	parsed, err := ParseZmapVersion(version)
+	if err == nil {
+		capabilities.Version = parsed
+		capabilities.VersionKnown = true
+	}
+	for feature, minimum := range featureVersions {
+		capabilities.Features[feature] = !capabilities.VersionKnown || parsed.AtLeast(minimum)
+	}
+	return capabilities
+}
+
+// Capabilities returns the version, the supported features, the modules and the output fields of the zmap binary.
+// They are discovered once and cached, see DiscoveryCache.
+func (s *scanner) Capabilities() (*Capabilities, error) {
+	return s.CapabilitiesContext(s.ctx)
+}
+
+// CapabilitiesContext is Capabilities with a context. Zmap is killed when ctx is done.
+func (s *scanner) CapabilitiesContext(ctx context.Context) (*Capabilities, error) {
+	capabilities, err := s.features(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if capabilities.ProbeModules, err = s.ListProbeModulesContext(ctx); err != nil {
+		return nil, err
+	}
+	if capabilities.OutputModules, err = s.ListOutputModulesContext(ctx); err != nil {
+		return nil, err
+	}
+	if capabilities.OutputFields, err = s.ListOutputFieldsContext(ctx); err != nil {
+		return nil, err
+	}
+	return capabilities, nil
+}
+
+// features returns the capabilities without modules and output fields, which only needs the version.
+func (s *scanner) features(ctx context.Context) (*Capabilities, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	version, err := s.discover(ctx, "--version")
+	if err != nil {
+		return nil, err
+	}
+	return newCapabilities(string(version)), nil
+}
+
+// requireFeature returns an error if the zmap binary of the scanner does not support feature.
+func (s *scanner) requireFeature(feature Feature) error {
+	capabilities, err := s.features(s.ctx)
+	if err != nil {
+		return err
+	}
+	if !capabilities.Has(feature) {
+		return fmt.Errorf("%s requires zmap >= %s, found %s", feature, featureVersions[feature], capabilities.Version)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package zmapgo
+
+import (
+	"testing"
+
+	"github.com/justmumu/zmapgo/zmaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseZmapVersion(t *testing.T) {
+	tests := []struct {
+		testDesc        string
+		version         string
+		expectedVersion ZmapVersion
+		isErrorExpected bool
+	}{
+		{
+			testDesc:        "With Release",
+			version:         "zmap 2.1.1",
+			expectedVersion: ZmapVersion{Major: 2, Minor: 1, Patch: 1},
+		},
+		{
+			testDesc:        "With Pre-Release",
+			version:         "zmap 4.0.0-RC1",
+			expectedVersion: ZmapVersion{Major: 4, PreRelease: "RC1"},
+		},
+		{
+			testDesc:        "Without Patch",
+			version:         "3.0",
+			expectedVersion: ZmapVersion{Major: 3},
+		},
+		{
+			testDesc:        "With Development Build",
+			version:         "zmap Development Build. Commit UNKNOWN",
+			isErrorExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			version, err := ParseZmapVersion(test.version)
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+			assert.Equal(t, test.expectedVersion, version)
+		})
+	}
+}
+
+func TestZmapVersion_AtLeast(t *testing.T) {
+	t.Log("Testing AtLeast function of ZmapVersion")
+	v211 := ZmapVersion{Major: 2, Minor: 1, Patch: 1}
+	v400rc1 := ZmapVersion{Major: 4, PreRelease: "RC1"}
+	v400 := ZmapVersion{Major: 4}
+
+	assert.True(t, v211.AtLeast(v211))
+	assert.False(t, v211.AtLeast(v400))
+	assert.True(t, v400.AtLeast(v211))
+	assert.True(t, v400.AtLeast(v400rc1))
+	assert.False(t, v400rc1.AtLeast(v400))
+	assert.Equal(t, "4.0.0-RC1", v400rc1.String())
+}
+
+func TestNewCapabilities(t *testing.T) {
+	tests := []struct {
+		testDesc            string
+		version             string
+		expectedSupported   []Feature
+		expectedUnsupported []Feature
+	}{
+		{
+			testDesc:            "With Zmap 2.1.1",
+			version:             "zmap 2.1.1",
+			expectedUnsupported: []Feature{FeatureIPv6, FeatureMultiPort, FeatureIPLayer, FeatureListOfIPs, FeatureDedup},
+		},
+		{
+			testDesc:            "With Zmap 3.0.0",
+			version:             "zmap 3.0.0",
+			expectedSupported:   []Feature{FeatureIPLayer, FeatureListOfIPs},
+			expectedUnsupported: []Feature{FeatureIPv6, FeatureMultiPort, FeatureDedup},
+		},
+		{
+			testDesc:          "With Zmap 4.1.1",
+			version:           "zmap 4.1.1",
+			expectedSupported: []Feature{FeatureIPv6, FeatureMultiPort, FeatureIPLayer, FeatureListOfIPs, FeatureDedup},
+		},
+		{
+			testDesc:          "With Development Build",
+			version:           "zmap Development Build. Commit UNKNOWN",
+			expectedSupported: []Feature{FeatureIPv6, FeatureMultiPort, FeatureIPLayer, FeatureListOfIPs, FeatureDedup},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			capabilities := newCapabilities(test.version)
+			for _, feature := range test.expectedSupported {
+				assert.True(t, capabilities.Has(feature), "Expected that %s is supported", feature)
+			}
+			for _, feature := range test.expectedUnsupported {
+				assert.False(t, capabilities.Has(feature), "Expected that %s is not supported", feature)
+			}
+		})
+	}
+}
+
+func TestCapabilities_Cached(t *testing.T) {
+	t.Log("Testing Capabilities function discovers the binary once")
+	binary := zmaptest.New(t, zmaptest.Config{Version: "2.1.1"})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	// Version is discovered while creating the scanner.
+	assert.Len(t, binary.Invocations(), 1)
+
+	for i := 0; i < 3; i++ {
+		capabilities, err := scanner.Capabilities()
+		if err != nil {
+			t.Error("Expected that error is not returned while detecting capabilities")
+		}
+		assert.Equal(t, ZmapVersion{Major: 2, Minor: 1, Patch: 1}, capabilities.Version)
+		assert.Equal(t, zmaptest.DefaultProbeModules, capabilities.ProbeModules)
+		assert.Equal(t, zmaptest.DefaultOutputModules, capabilities.OutputModules)
+		assert.Len(t, capabilities.OutputFields, len(zmaptest.DefaultOutputFields))
+	}
+	// Modules and output fields are discovered once.
+	assert.Len(t, binary.Invocations(), 4)
+
+	err = scanner.AddOptions(WithIPLayer())
+	t.Logf("Returned Error: %v", err)
+	if assert.Error(t, err) {
+		assert.Equal(t, "iplayer requires zmap >= 3.0.0, found 2.1.1", err.Error())
+	}
+}
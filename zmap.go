@@ -2,13 +2,11 @@ package zmapgo
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
-	"io/fs"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -21,15 +19,39 @@ import (
 type BlockingScanner interface {
 	AddOptions(options ...Option) error
 	RunBlocking() (results []map[string]interface{}, traces []LogLine, debugs []LogLine, warnings []LogLine, infos []LogLine, fatals []LogLine, err error)
+	RunBlockingContext(ctx context.Context) (results []map[string]interface{}, traces []LogLine, debugs []LogLine, warnings []LogLine, infos []LogLine, fatals []LogLine, err error)
+	Run(ctx context.Context) (*ScanResult, error)
+	RunStream(ctx context.Context, handler ResultHandler) (*ScanResult, error)
+	OnLog(handler LogHandler, levels ...LogLevel) (unsubscribe func())
+	OnProgress(handler ProgressHandler) (unsubscribe func())
+	GetScanMetadata() *ScanMetadata
 	ListProbeModules() ([]string, error)
+	ListProbeModulesContext(ctx context.Context) ([]string, error)
 	ListOutputModules() ([]string, error)
+	ListOutputModulesContext(ctx context.Context) ([]string, error)
 	ListOutputFields() ([]OutputField, error)
+	ListOutputFieldsContext(ctx context.Context) ([]OutputField, error)
+	ListOutputFieldsFor(probeModule string) ([]OutputField, error)
+	ListOutputFieldsForContext(ctx context.Context, probeModule string) ([]OutputField, error)
 	GetVersion() (string, error)
+	GetVersionContext(ctx context.Context) (string, error)
+	Capabilities() (*Capabilities, error)
+	CapabilitiesContext(ctx context.Context) (*Capabilities, error)
+	Validate() error
+	ValidateContext(ctx context.Context) error
+	WriteZmapConfig(ioWriter io.Writer) error
+	ZmapConfig() string
+	Options() []Argument
+	CommandLine() []string
+	RemoveOption(flag string) error
+	ReplaceOption(option Option) error
+	Clone() BlockingScanner
 }
 
 type AsyncScanner interface {
 	AddOptions(options ...Option) error
-	RunAsync() error
+	RunAsync() (*ScanJob, error)
+	RunAsyncContext(ctx context.Context) (*ScanJob, error)
 	Wait() error
 	GetTraceMessages() []LogLine
 	GetDebugMessages() []LogLine
@@ -37,10 +59,30 @@ type AsyncScanner interface {
 	GetInfoMessages() []LogLine
 	GetFatalMessages() []LogLine
 	GetResults() []map[string]interface{}
+	OnLog(handler LogHandler, levels ...LogLevel) (unsubscribe func())
+	OnProgress(handler ProgressHandler) (unsubscribe func())
+	GetScanMetadata() *ScanMetadata
 	ListProbeModules() ([]string, error)
+	ListProbeModulesContext(ctx context.Context) ([]string, error)
 	ListOutputModules() ([]string, error)
+	ListOutputModulesContext(ctx context.Context) ([]string, error)
 	ListOutputFields() ([]OutputField, error)
+	ListOutputFieldsContext(ctx context.Context) ([]OutputField, error)
+	ListOutputFieldsFor(probeModule string) ([]OutputField, error)
+	ListOutputFieldsForContext(ctx context.Context, probeModule string) ([]OutputField, error)
 	GetVersion() (string, error)
+	GetVersionContext(ctx context.Context) (string, error)
+	Capabilities() (*Capabilities, error)
+	CapabilitiesContext(ctx context.Context) (*Capabilities, error)
+	Validate() error
+	ValidateContext(ctx context.Context) error
+	WriteZmapConfig(ioWriter io.Writer) error
+	ZmapConfig() string
+	Options() []Argument
+	CommandLine() []string
+	RemoveOption(flag string) error
+	ReplaceOption(option Option) error
+	CloneAsync() AsyncScanner
 }
 
 // InitOptions is initialization option for the Scanner.
@@ -51,6 +93,10 @@ type InitOption func(*scanner) error
 // Option adds or remove zmap command line arguments.
 type Option func(*scanner) error
 
+// ResultHandler is called for every result row of a streaming scan.
+// Returning an error stops the scan.
+type ResultHandler func(result map[string]interface{}) error
+
 type OutputField struct {
 	Name        string
 	Type        string
@@ -63,25 +109,98 @@ type LogLine struct {
 	Message string
 }
 
+// ScanResult holds everything about a finished scan.
+type ScanResult struct {
+	// Results is filled by Run. It is empty for RunStream, since rows are passed to the handler.
+	Results []map[string]interface{}
+
+	Traces   []LogLine
+	Debugs   []LogLine
+	Warnings []LogLine
+	Infos    []LogLine
+	Fatals   []LogLine
+
+	// ExitCode is the exit code of zmap process. It is -1 if the process is killed.
+	ExitCode  int
+	StartTime time.Time
+	EndTime   time.Time
+	Duration  time.Duration
+
+	// CommandLine is the binary path and the arguments used to run zmap.
+	CommandLine []string
+
+	// Metadata is nil if zmap did not write the metadata.
+	Metadata *ScanMetadata
+
+	// DryRunPackets is filled if WithDryrun is passed. Results are empty in dryrun mode.
+	DryRunPackets []DryRunPacket
+}
+
+func (r *ScanResult) setLogs(collector *logCollector) {
+	r.Traces = collector.traces
+	r.Debugs = collector.debugs
+	r.Warnings = collector.warnings
+	r.Infos = collector.infos
+	r.Fatals = collector.fatals
+}
+
 // Scanner is represents the zmap scanner.
 type scanner struct {
-	args       []string
+	args arguments
+	// targets are passed with WithTargets. They are added to args while running.
+	targets []string
+	// ipv6Targets are passed with WithTargets. They are written to a temporary ipv6 target file while running.
+	ipv6Targets []string
+	// blocklist and allowlist are written to temporary files while running.
+	blocklist  *Blocklist
+	allowlist  *Blocklist
 	binaryPath string
 	ctx        context.Context
+	runner     Runner
 
-	waiter sync.WaitGroup
+	// gracePeriod is the time to wait for zmap to exit after SIGINT, before killing it.
+	gracePeriod       time.Duration
+	gracePeriodPassed bool
 
-	asyncError   error
-	asyncTrace   []LogLine
-	asyncDebug   []LogLine
-	asyncWarning []LogLine
-	asyncInfo    []LogLine
-	asyncFatal   []LogLine
-	asyncResults []map[string]interface{}
+	// binaryPathPassed is true if binary path is passed with WithBinaryPath.
+	binaryPathPassed bool
+
+	logSubscribers      logSubscribers
+	progressSubscribers progressSubscribers
+
+	// metadata is the metadata of the last RunBlocking call. See GetScanMetadata.
+	metadataMutex sync.Mutex
+	metadata      *ScanMetadata
+
+	// discoveryCache keeps the outputs of zmap discovery commands. See DiscoveryCache.
+	discoveryCache *DiscoveryCache
+
+	// localRunner is true if zmap is run by the default runner, so it can read and write local temporary files.
+	localRunner bool
+
+	// lastJob is the last job started by RunAsync. It is used by the deprecated async getters.
+	lastJobMutex sync.Mutex
+	lastJob      *ScanJob
 }
 
 // Creates new Scanner Interface
 func NewBlockingScanner(initOptions ...InitOption) (BlockingScanner, error) {
+	sc, err := newScanner(initOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func NewAsyncScanner(initOptions ...InitOption) (AsyncScanner, error) {
+	sc, err := newScanner(initOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+func newScanner(initOptions ...InitOption) (*scanner, error) {
 	sc := &scanner{}
 
 	for _, initOption := range initOptions {
@@ -90,13 +209,12 @@ func NewBlockingScanner(initOptions ...InitOption) (BlockingScanner, error) {
 		}
 	}
 
-	// After this block binaryPath filled.
-	if sc.binaryPath == "" {
-		var err error
-		sc.binaryPath, err = exec.LookPath("zmap")
-		if err != nil {
-			return nil, ErrZmapNotInstalled
-		}
+	// create runner if not already created
+	// The binary path is only resolved on the local host if zmap is run by the default runner.
+	sc.localRunner = sc.runner == nil
+	localRunner := sc.localRunner
+	if localRunner {
+		sc.runner = NewExecRunner()
 	}
 
 	// create ctx if not already created
@@ -104,30 +222,46 @@ func NewBlockingScanner(initOptions ...InitOption) (BlockingScanner, error) {
 		sc.ctx = context.Background()
 	}
 
-	return sc, nil
-}
-
-func NewAsyncScanner(initOptions ...InitOption) (AsyncScanner, error) {
-	sc := &scanner{}
+	if !sc.gracePeriodPassed {
+		sc.gracePeriod = defaultGracePeriod
+	}
 
-	for _, initOption := range initOptions {
-		if err := initOption(sc); err != nil {
-			return nil, err
+	// Discovery is shared between scanners only if zmap is run on the local host.
+	if sc.discoveryCache == nil {
+		if localRunner {
+			sc.discoveryCache = defaultDiscoveryCache
+		} else {
+			sc.discoveryCache = NewDiscoveryCache()
 		}
 	}
 
 	// After this block binaryPath filled.
 	if sc.binaryPath == "" {
-		var err error
-		sc.binaryPath, err = exec.LookPath("zmap")
-		if err != nil {
-			return nil, ErrZmapNotInstalled
+		if !localRunner {
+			sc.binaryPath = "zmap"
+		} else {
+			var err error
+			sc.binaryPath, err = exec.LookPath("zmap")
+			if err != nil {
+				return nil, ErrZmapNotInstalled
+			}
 		}
 	}
 
-	// create ctx if not already created
-	if sc.ctx == nil {
-		sc.ctx = context.Background()
+	if sc.binaryPathPassed {
+		// check binary path exists
+		if localRunner {
+			if _, err := os.Stat(sc.binaryPath); errors.Is(err, os.ErrNotExist) {
+				return nil, errors.New("given binary path does not exists")
+			}
+		}
+
+		// check real zmap binary
+		out, _ := sc.discover(sc.ctx, "--version")
+		trimed := strings.Trim(string(out), "\n")
+		if !strings.Contains(trimed, "zmap") {
+			return nil, errors.New("given binary is not real zmap binary")
+		}
 	}
 
 	return sc, nil
@@ -142,332 +276,593 @@ func (s *scanner) AddOptions(options ...Option) error {
 	return nil
 }
 
+// Run runs the scan and returns everything about it in a ScanResult.
+// All result rows are kept in memory. Use RunStream for large scans.
+func (s *scanner) Run(ctx context.Context) (*ScanResult, error) {
+	var results []map[string]interface{}
+	scanResult, err := s.RunStream(ctx, func(result map[string]interface{}) error {
+		results = append(results, result)
+		return nil
+	})
+	// Results are returned even if the scan is stopped, since they were already written by zmap.
+	if scanResult != nil {
+		scanResult.Results = results
+	}
+	return scanResult, err
+}
+
+// RunBlocking runs the scan and returns results and logs.
+// It is kept for compatibility. Run returns more informations about the scan.
 func (s *scanner) RunBlocking() (results []map[string]interface{}, traces []LogLine, debugs []LogLine, warnings []LogLine, infos []LogLine, fatals []LogLine, err error) {
-	var (
-		stdout, stderr bytes.Buffer
-	)
+	return s.RunBlockingContext(s.ctx)
+}
+
+// RunBlockingContext is RunBlocking with a context for this run.
+// The scan is stopped when ctx or the context of the scanner is done.
+func (s *scanner) RunBlockingContext(ctx context.Context) (results []map[string]interface{}, traces []LogLine, debugs []LogLine, warnings []LogLine, infos []LogLine, fatals []LogLine, err error) {
+	scanResult, err := s.Run(ctx)
+	if scanResult == nil {
+		return nil, traces, debugs, warnings, infos, fatals, err
+	}
+	// Metadata is not returned by RunBlocking, so it is kept for GetScanMetadata.
+	s.setScanMetadata(scanResult.Metadata)
+	return scanResult.Results, scanResult.Traces, scanResult.Debugs, scanResult.Warnings, scanResult.Infos, scanResult.Fatals, err
+}
+
+// RunStream runs the scan and passes every result row to handler while zmap is still running.
+// Rows are parsed line by line from stdout, so memory usage does not grow with the size of the result set.
+// The handler is called from a single goroutine. A slow handler applies backpressure to zmap,
+// since stdout is not read until the handler returns.
+// If the handler returns an error, the zmap process is killed and that error is returned.
+// If --output-file is passed, zmap writes results to the file instead of stdout,
+// so the rows are passed to the handler after zmap exits.
+// Results field of the returned ScanResult is always empty.
+func (s *scanner) RunStream(ctx context.Context, handler ResultHandler) (*ScanResult, error) {
+	if handler == nil {
+		return nil, errors.New("result handler cannot be nil")
+	}
+	if ctx == nil {
+		ctx = s.ctx
+	}
+	return s.run(ctx, handler)
+}
 
+// callContext returns a context that is done when ctx or the context of the scanner is done.
+// The returned cancel function must be called when the call is finished.
+func (s *scanner) callContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = s.ctx
+	}
+	callCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancel()
+		case <-callCtx.Done():
+		}
+	}()
+	return callCtx, cancel
+}
+
+// runConfig holds the informations about how zmap will write its results and logs.
+type runConfig struct {
+	dryrunPassed       bool
+	outputFilePassed   bool
+	logFilePassed      bool
+	logDirectoryPassed bool
+	outputFieldsPassed bool
+
+	statusUpdatesFilePassed bool
+
+	outputFilePath        string
+	logFilePath           string
+	logDirectoryPath      string
+	statusUpdatesFilePath string
+	metadataFilePath      string
+	outputFields          []string
+
+	// args are the arguments of the scanner with the defaults of this run.
+	// The scanner arguments are never changed while running, so a scanner can be run any number of times.
+	args arguments
+	// extraArgs are added to zmap arguments only for this run.
+	extraArgs []string
+	// tempFiles are created for this run and removed after it.
+	tempFiles []string
+}
+
+// csvHeader returns the header that should be used while parsing csv results.
+// If user requested one field, zmap will not add csv headers. So it is returned to add it manually.
+// Otherwise nil is returned and the header is read from the output.
+func (c *runConfig) csvHeader() []string {
+	if c.outputFieldsPassed && len(c.outputFields) == 1 {
+		return c.outputFields
+	}
+	return nil
+}
+
+// prepareRun looks for passed arguments and adds default ones.
+func (s *scanner) prepareRun(ctx context.Context) (*runConfig, error) {
 	var (
-		dryrunPassed         bool = false
-		outputFilePassed     bool = false
-		logFilePassed        bool = false
-		logDirectoryPassed   bool = false
-		verbosityLevelPassed bool = false
-		outputFieldsPassed   bool = false
+		cfg runConfig
+		err error
 	)
+	// Short flags are passed with their long names, so that the defaults below replace them instead of
+	// being passed twice. Arguments of the config file are looked up too, since zmap reads them.
+	cfg.args = s.args.longFlags()
+	effective := s.effectiveArguments()
 
 	// Look for --dryrun
-	_, err = s.getArgument("--dryrun")
-	if err == nil {
-		dryrunPassed = true
-	}
+	_, cfg.dryrunPassed = effective.value("--dryrun")
 
 	// Look for --log-file
-	logFilePath, err := s.getArgument("--log-file")
-	if err == nil {
-		logFilePassed = true
+	cfg.logFilePath, cfg.logFilePassed = effective.value("--log-file")
+	if cfg.logFilePassed {
+		cfg.logFilePath, err = filepath.Abs(cfg.logFilePath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if logFilePassed {
-		logFilePath, err = filepath.Abs(logFilePath)
+	// Look for --log-directory
+	cfg.logDirectoryPath, cfg.logDirectoryPassed = effective.value("--log-directory")
+	if cfg.logDirectoryPassed {
+		cfg.logDirectoryPath, err = filepath.Abs(cfg.logDirectoryPath)
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			return nil, err
 		}
 	}
 
-	// Look for --log-directory
-	logDirectoryPath, err := s.getArgument("--log-directory")
-	if err == nil {
-		logDirectoryPassed = true
+	// Look for --status-updates-file
+	cfg.statusUpdatesFilePath, cfg.statusUpdatesFilePassed = effective.value("--status-updates-file")
+	if cfg.statusUpdatesFilePassed {
+		cfg.statusUpdatesFilePath, err = filepath.Abs(cfg.statusUpdatesFilePath)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	if logDirectoryPassed {
-		logDirectoryPath, err = filepath.Abs(logDirectoryPath)
+	// Look for --metadata-file
+	if metadataFilePath, ok := effective.value("--metadata-file"); ok {
+		cfg.metadataFilePath, err = filepath.Abs(metadataFilePath)
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			return nil, err
 		}
 	}
 
 	// Look for --verbosity
-	_, err = s.getArgument("--verbosity")
-	if err == nil {
-		verbosityLevelPassed = true
+	if _, ok := effective.value("--verbosity"); !ok {
+		cfg.args.add("--verbosity", string(VerbosityLevel5))
 	}
 
-	if !verbosityLevelPassed {
-		optionFunc := WithVerbosity(VerbosityLevel5)
-		err = optionFunc(s)
+	// look for --output-file
+	outputFilePath, ok := effective.value("--output-file")
+	if ok && outputFilePath != "-" {
+		cfg.outputFilePassed = true
+		cfg.outputFilePath, err = filepath.Abs(outputFilePath)
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			return nil, err
 		}
 	}
 
-	// look for --output-file
-	outputFilePath, err := s.getArgument("--output-file")
-	if err == nil {
-		outputFilePassed = true
+	// look for --output-fields
+	outputFields, ok := effective.value("--output-fields")
+	if ok {
+		cfg.outputFieldsPassed = true
+		cfg.outputFields = strings.Split(outputFields, ",")
+
+		// Rows of a multi-port scan are useless without the port they came from.
+		if _, ok := effective.value("--target-ports"); ok && !containsString(cfg.outputFields, "dport") {
+			cfg.outputFields = append(cfg.outputFields, "dport")
+			cfg.args.set(Argument{Flag: "--output-fields", Value: strings.Join(cfg.outputFields, ","), HasValue: true})
+		}
+	} else {
+		availableOutputFields, err := s.ListOutputFieldsContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, aFields := range availableOutputFields {
+			cfg.outputFields = append(cfg.outputFields, aFields.Name)
+		}
+		cfg.args.add("--output-fields", strings.Join(cfg.outputFields, ","))
 	}
 
-	if outputFilePassed {
-		outputFilePath, err = filepath.Abs(outputFilePath)
+	// Metadata is always parsed. If user did not pass a metadata file, a temporary one is used.
+	// Zmap may not see local temporary files if it is run by a custom runner, so metadata is only parsed
+	// from the file passed by user then.
+	if cfg.metadataFilePath == "" && s.localRunner {
+		cfg.metadataFilePath, err = cfg.writeTempFile("zmapgo-metadata-*.json", nil)
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			return nil, err
 		}
+		cfg.extraArgs = append(cfg.extraArgs, "--metadata-file", cfg.metadataFilePath)
 	}
 
-	// look for --output-fields
-	outputFields, err := s.getArgument("--output-fields")
-	if err == nil {
-		outputFieldsPassed = true
+	// Blocklists are written to temporary files in zmap format.
+	if (s.blocklist != nil || s.allowlist != nil) && !s.localRunner {
+		cfg.cleanup()
+		return nil, errors.New("blocklist and allowlist cannot be used with a custom runner, since they are passed with local temporary files")
+	}
+	if s.blocklist != nil {
+		path, err := cfg.writeTempFile("zmapgo-blacklist-*.conf", s.blocklist)
+		if err != nil {
+			cfg.cleanup()
+			return nil, err
+		}
+		cfg.extraArgs = append(cfg.extraArgs, "--blacklist-file", path)
+	}
+	if s.allowlist != nil {
+		path, err := cfg.writeTempFile("zmapgo-whitelist-*.conf", s.allowlist)
+		if err != nil {
+			cfg.cleanup()
+			return nil, err
+		}
+		cfg.extraArgs = append(cfg.extraArgs, "--whitelist-file", path)
 	}
 
-	if !outputFieldsPassed {
-		availableOutputFields, err := s.ListOutputFields()
+	// Targets are written to a temporary whitelist file if they are too many to pass as arguments.
+	// It is only possible if user did not pass a whitelist, since zmap accepts one, and zmap is run on the local host.
+	_, whitelistPassed := effective.value("--whitelist-file")
+	if len(s.targets) > maxTargetArgs && !whitelistPassed && s.allowlist == nil && s.localRunner {
+		path, err := cfg.writeTempFile("zmapgo-targets-*.txt", targetsWriter(s.targets))
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			cfg.cleanup()
+			return nil, err
 		}
-		var newOutputFields []string
-		for _, aFields := range availableOutputFields {
-			newOutputFields = append(newOutputFields, aFields.Name)
+		cfg.extraArgs = append(cfg.extraArgs, "--whitelist-file", path)
+	} else {
+		cfg.extraArgs = append(cfg.extraArgs, s.targets...)
+	}
+
+	// IPv6 targets are written to a temporary ipv6 target file, since zmap does not accept them as arguments.
+	if len(s.ipv6Targets) > 0 {
+		if !s.localRunner {
+			cfg.cleanup()
+			return nil, errors.New("ipv6 targets cannot be used with a custom runner, since they are passed with a local temporary file. Use WithIPv6TargetFile instead")
 		}
-		optionFunc := WithOutputFields(newOutputFields)
-		err = optionFunc(s)
+		path, err := cfg.writeTempFile("zmapgo-ipv6-targets-*.txt", targetsWriter(s.ipv6Targets))
+		if err != nil {
+			cfg.cleanup()
+			return nil, err
+		}
+		cfg.extraArgs = append(cfg.extraArgs, "--ipv6-target-file", path)
+	}
+
+	return &cfg, nil
+}
+
+// maxTargetArgs is the maximum number of targets passed as arguments.
+const maxTargetArgs = 256
+
+// targetsWriter writes targets one target per line.
+type targetsWriter []string
+
+func (t targetsWriter) WriteTo(ioWriter io.Writer) (int64, error) {
+	writer := bufio.NewWriter(ioWriter)
+	var written int64
+	for _, target := range t {
+		n, err := writer.WriteString(target + "\n")
+		written += int64(n)
 		if err != nil {
-			return nil, traces, debugs, warnings, infos, fatals, err
+			return written, err
+		}
+	}
+	return written, writer.Flush()
+}
+
+// writeTempFile creates a temporary file that is removed by cleanup, and writes content into it if it is not nil.
+func (c *runConfig) writeTempFile(pattern string, content io.WriterTo) (string, error) {
+	file, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	c.tempFiles = append(c.tempFiles, file.Name())
+
+	if content != nil {
+		if _, err := content.WriteTo(file); err != nil {
+			return "", err
+		}
+	}
+	return file.Name(), nil
+}
+
+// cleanup removes the temporary files created for this run.
+func (c *runConfig) cleanup() {
+	for _, path := range c.tempFiles {
+		_ = os.Remove(path)
+	}
+	c.tempFiles = nil
+}
+
+// readMetadata parses the metadata file written by zmap.
+// It returns nil if zmap did not write the metadata. Ex: process is killed.
+func (c *runConfig) readMetadata() (*ScanMetadata, error) {
+	if c.metadataFilePath == "" {
+		return nil, nil
+	}
+	metadataFile, err := os.Open(c.metadataFilePath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
 		}
+		return nil, err
+	}
+	defer metadataFile.Close()
+
+	fileInfo, err := metadataFile.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fileInfo.Size() == 0 {
+		return nil, nil
+	}
+
+	return ParseScanMetadata(metadataFile)
+}
+
+// run runs the zmap process and passes every parsed result row to handler.
+// Log lines are parsed while zmap is running and passed to log subscribers.
+// The returned ScanResult is nil only if zmap could not be started.
+func (s *scanner) run(ctx context.Context, handler ResultHandler) (*ScanResult, error) {
+	if err := s.ValidateContext(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg, err := s.prepareRun(ctx)
+	if err != nil {
+		return nil, err
 	}
+	defer cfg.cleanup()
 
-	args := s.args
+	args := append(cfg.args.strings(), cfg.extraArgs...)
 
 	// Prepare zmap process
-	cmd := exec.Command(s.binaryPath, args...)
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	command := &Command{
+		Path: s.binaryPath,
+		Args: args,
+	}
+
+	// Results are streamed from stdout only if zmap writes them there.
+	// In dryrun mode, zmap prints the packets to stdout instead of sending them.
+	var stdout *io.PipeReader
+	var stdoutWriter *io.PipeWriter
+	if cfg.dryrunPassed || !cfg.outputFilePassed {
+		stdout, stdoutWriter = io.Pipe()
+		command.Stdout = stdoutWriter
+	}
+
+	// Logs are written to stderr unless log file or log directory is passed.
+	// Stderr is always read, since zmap prints status updates there.
+	stderr, stderrWriter := io.Pipe()
+	command.Stderr = stderrWriter
+	closeWriters := func() {
+		if stdoutWriter != nil {
+			_ = stdoutWriter.Close()
+		}
+		_ = stderrWriter.Close()
+	}
+
+	var resolveLogPath func() string
+	switch {
+	case cfg.logFilePassed:
+		resolveLogPath = func() string { return cfg.logFilePath }
+	case cfg.logDirectoryPassed:
+		resolveLogPath = logDirectoryResolver(cfg.logDirectoryPath)
+	}
+
+	// process is set before any line is read.
+	var process Process
+	collector := &logCollector{
+		subscribers: &s.logSubscribers,
+		onFatal: func() {
+			// zmap cannot continue after a fatal error. Don't wait for it to exit.
+			_ = process.Kill()
+		},
+	}
+
+	scanResult := &ScanResult{
+		CommandLine: append([]string{s.binaryPath}, args...),
+		ExitCode:    -1,
+		StartTime:   time.Now(),
+	}
 
 	// Run zmap process
-	err = cmd.Start()
+	process, err = s.runner.Start(ctx, command)
 	if err != nil {
-		return nil, traces, debugs, warnings, infos, fatals, err
+		closeWriters()
+		return nil, err
+	}
+
+	var readers sync.WaitGroup
+	var streamErr error
+	var stderrTail lineTail
+	if stdout != nil {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			if cfg.dryrunPassed {
+				scanResult.DryRunPackets, streamErr = ParseDryRunPackets(stdout)
+			} else {
+				streamErr = s.parseCsvStream(stdout, cfg.csvHeader(), handler)
+			}
+			if streamErr != nil {
+				// Stop zmap and drain the rest of the output so that it can exit.
+				_ = process.Kill()
+				_, _ = io.Copy(ioutil.Discard, stdout)
+			}
+		}()
+	}
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		readLines(stderr, func(line string) {
+			if progress, ok := parseStatusLine(line); ok {
+				if !cfg.statusUpdatesFilePassed {
+					s.progressSubscribers.publish(progress)
+				}
+				return
+			}
+			stderrTail.add(line)
+			if resolveLogPath == nil {
+				collector.addLine(line)
+			}
+		})
+	}()
+
+	stopTail := make(chan struct{})
+	var tails sync.WaitGroup
+	if resolveLogPath != nil {
+		tails.Add(1)
+		go func() {
+			defer tails.Done()
+			tailFile(resolveLogPath, stopTail, collector.addLine)
+		}()
+	}
+	if cfg.statusUpdatesFilePassed {
+		tails.Add(1)
+		go func() {
+			defer tails.Done()
+			parser := &statusUpdatesParser{}
+			tailFile(func() string { return cfg.statusUpdatesFilePath }, stopTail, func(line string) {
+				if progress, ok := parser.parseLine(line); ok {
+					s.progressSubscribers.publish(progress)
+				}
+			})
+		}()
 	}
 
 	// Make a goroutine to notify the select when the scan is done.
+	// Pipes are closed after the process exits, so that readers reach the end of the output.
 	done := make(chan error, 1)
 	go func() {
-		done <- cmd.Wait()
+		waitErr := process.Wait()
+		closeWriters()
+		readers.Wait()
+		scanResult.EndTime = time.Now()
+		scanResult.Duration = scanResult.EndTime.Sub(scanResult.StartTime)
+		scanResult.ExitCode = exitCode(waitErr)
+		close(stopTail)
+		tails.Wait()
+		done <- streamErr
 	}()
 
+	var cancelErr error
 	select {
 	case <-s.ctx.Done():
-		// Context was done before the scan was finished.
-		// The process is killed and a timeout error is returned.
-		_ = cmd.Process.Kill()
-		return nil, traces, debugs, warnings, infos, fatals, ErrScanTimeout
-	case <-done:
+		cancelErr = s.ctx.Err()
+		err = s.stopProcess(process, done)
+	case <-ctx.Done():
+		cancelErr = ctx.Err()
+		err = s.stopProcess(process, done)
+	case err = <-done:
 		// Process zmap is done.
-		// Output will be parsed according to passing arguments.
+	}
 
-		// Start Log Parsing
-		if logFilePassed {
-			// Then parse Trace, Debug, Warning, Info and Fatal Message from log file.
-			logFile, err := os.OpenFile(logFilePath, os.O_RDONLY, os.ModePerm)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
-			defer logFile.Close()
+	scanResult.setLogs(collector)
+	if cancelErr != nil {
+		// Context was done before the scan was finished.
+		// Everything zmap wrote until it stopped is returned with the timeout error.
+		// The last row of the output file may be incomplete, so parse errors are ignored.
+		scanResult.Metadata, _ = cfg.readMetadata()
+		if err == nil && !cfg.dryrunPassed && cfg.outputFilePassed {
+			_ = s.parseOutputFile(cfg, handler)
+		}
+		return scanResult, fmt.Errorf("%w: %v", ErrScanTimeout, cancelErr)
+	}
 
-			traces, debugs, warnings, infos, fatals, err = s.parseLogs(logFile)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
+	if err != nil {
+		return scanResult, err
+	}
+	if scanResult.ExitCode != 0 || len(collector.fatals) > 0 {
+		return scanResult, &ZmapExitError{
+			ExitCode: scanResult.ExitCode,
+			Stderr:   stderrTail.lines,
+			Fatals:   collector.fatals,
+			Err:      fatalCause(collector.fatals),
 		}
+	}
+	if collector.err != nil {
+		return scanResult, collector.err
+	}
 
-		if logDirectoryPassed {
-			// Then parse Trace, Debug, Warning, Info and Fatal Message from latest created file in log directory.
-			fileInfos, err := ioutil.ReadDir(logDirectoryPath)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
-			// Find latest log file
-			timeLayout := "zmap-2006-01-02T150405-0700.log"
-			var latestFile fs.FileInfo
-			for _, fileInfo := range fileInfos {
-				if !fileInfo.IsDir() {
-					tNew, err := time.Parse(timeLayout, fileInfo.Name())
-					if err == nil {
-						if latestFile != nil {
-							tOld, _ := time.Parse(timeLayout, latestFile.Name())
-							if tNew.After(tOld) {
-								latestFile = fileInfo
-							}
-						} else {
-							latestFile = fileInfo
-						}
-					}
-				}
-			}
-			// Now Parse Log file.
-			logDirectoryFile, err := os.OpenFile(filepath.Join(logDirectoryPath, latestFile.Name()), os.O_RDONLY, os.ModePerm)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
-			defer logDirectoryFile.Close()
+	scanResult.Metadata, err = cfg.readMetadata()
+	if err != nil {
+		return scanResult, err
+	}
 
-			traces, debugs, warnings, infos, fatals, err = s.parseLogs(logDirectoryFile)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
+	// Results written to stdout are already passed to handler.
+	if !cfg.dryrunPassed && cfg.outputFilePassed {
+		if err := s.parseOutputFile(cfg, handler); err != nil {
+			return scanResult, err
 		}
+	}
+	return scanResult, nil
+}
 
-		if !logDirectoryPassed && !logFilePassed {
-			// Then parse Trace, Debug, Warning, Info and Fatal Message from stderr
-			traces, debugs, warnings, infos, fatals, err = s.parseLogs(&stderr)
-			if err != nil {
-				return nil, traces, debugs, warnings, infos, fatals, err
-			}
-		}
-		// End Log Parsing
-
-		// Start Result parsing
-		if !dryrunPassed {
-			if outputFieldsPassed {
-				// User passed output fields.
-				// If user requested one field. zmap will not add csv headers.
-				outputFieldsSplitted := strings.Split(outputFields, ",")
-
-				if len(outputFieldsSplitted) == 1 {
-					// There is no csv headers
-					if outputFilePassed {
-						// Read from file, but add csv header manually.
-						outputFile, err := os.Open(outputFilePath)
-						if err != nil {
-							return nil, traces, debugs, warnings, infos, fatals, err
-						}
-						defer outputFile.Close()
-
-						sc := bufio.NewScanner(outputFile)
-
-						for sc.Scan() {
-							line := sc.Text()
-							result := map[string]interface{}{
-								outputFieldsSplitted[0]: line,
-							}
-							results = append(results, result)
-						}
-					} else {
-						// Read from stdout, but add csv header manually.
-						sc := bufio.NewScanner(&stdout)
-
-						for sc.Scan() {
-							line := sc.Text()
-							result := map[string]interface{}{
-								outputFieldsSplitted[0]: line,
-							}
-							results = append(results, result)
-						}
-					}
-				} else {
-					// There is csv headers
-					if outputFilePassed {
-						// Read from file
-						outputFile, err := os.Open(outputFilePath)
-						if err != nil {
-							return nil, traces, debugs, warnings, infos, fatals, err
-						}
-						defer outputFile.Close()
-
-						results, err = s.parseCsvOutputFile(outputFile)
-						if err != nil {
-							return nil, traces, debugs, warnings, infos, fatals, err
-						}
-					} else {
-						// Read from stdout
-						// Stdout contains only results.
-						results, err = s.parseCsvOutputFile(&stdout)
-						if err != nil {
-							return nil, traces, debugs, warnings, infos, fatals, err
-						}
-					}
-				}
-			} else {
-				// We added all output fields by default.
-				// So there is csv headers.
-				if outputFilePassed {
-					outputFile, err := os.Open(outputFilePath)
-					if err != nil {
-						return nil, traces, debugs, warnings, infos, fatals, err
-					}
-					defer outputFile.Close()
-
-					results, err = s.parseCsvOutputFile(outputFile)
-					if err != nil {
-						return nil, traces, debugs, warnings, infos, fatals, err
-					}
-				} else {
-					// Read from stdout
-					// Stdout contains only results.
-					results, err = s.parseCsvOutputFile(&stdout)
-					if err != nil {
-						return nil, traces, debugs, warnings, infos, fatals, err
-					}
-				}
+// stopProcess asks zmap to stop with SIGINT, and kills it if it does not exit in the grace period.
+// It returns the error sent to done after the process exits.
+func (s *scanner) stopProcess(process Process, done <-chan error) error {
+	if s.gracePeriod > 0 {
+		if err := process.Signal(os.Interrupt); err == nil {
+			timer := time.NewTimer(s.gracePeriod)
+			defer timer.Stop()
+			select {
+			case err := <-done:
+				return err
+			case <-timer.C:
 			}
 		}
 	}
-	return
+	_ = process.Kill()
+	return <-done
 }
 
-func (s *scanner) RunAsync() error {
-	s.waiter.Add(1)
-	go func() {
-		results, traces, debugs, warnings, infos, fatals, err := s.RunBlocking()
-		if err != nil {
-			s.asyncError = err
-			s.waiter.Done()
-			return
-		}
-		s.asyncResults = results
-		s.asyncTrace = traces
-		s.asyncDebug = debugs
-		s.asyncWarning = warnings
-		s.asyncInfo = infos
-		s.asyncFatal = fatals
-		s.waiter.Done()
-	}()
-	return nil
-}
-
-func (s *scanner) Wait() error {
-	// Wait until all wait group finished.
-	s.waiter.Wait()
-	return s.asyncError
-}
+// parseOutputFile passes every result row in the output file to handler.
+func (s *scanner) parseOutputFile(cfg *runConfig, handler ResultHandler) error {
+	outputFile, err := os.Open(cfg.outputFilePath)
+	if err != nil {
+		return err
+	}
+	defer outputFile.Close()
 
-func (s *scanner) GetTraceMessages() []LogLine {
-	return s.asyncTrace
+	return s.parseCsvStream(outputFile, cfg.csvHeader(), handler)
 }
 
-func (s *scanner) GetDebugMessages() []LogLine {
-	return s.asyncDebug
-}
+// GetScanMetadata returns the scan metadata of the last job started by RunAsync, or of the last RunBlocking
+// call if RunAsync is not used. It returns nil if zmap did not write the metadata.
+// Run and RunStream don't change it.
+//
+// Deprecated: Use Metadata of the ScanResult returned by Run, RunStream or ScanJob.Result.
+// It belongs to one run, while GetScanMetadata is shared by the runs of the scanner.
+func (s *scanner) GetScanMetadata() *ScanMetadata {
+	s.lastJobMutex.Lock()
+	job := s.lastJob
+	s.lastJobMutex.Unlock()
+	if job != nil {
+		return s.lastJobResult().Metadata
+	}
 
-func (s *scanner) GetWarningMessages() []LogLine {
-	return s.asyncWarning
+	s.metadataMutex.Lock()
+	defer s.metadataMutex.Unlock()
+	return s.metadata
 }
 
-func (s *scanner) GetInfoMessages() []LogLine {
-	return s.asyncInfo
+func (s *scanner) setScanMetadata(metadata *ScanMetadata) {
+	s.metadataMutex.Lock()
+	defer s.metadataMutex.Unlock()
+	s.metadata = metadata
 }
 
-func (s *scanner) GetFatalMessages() []LogLine {
-	return s.asyncFatal
+func (s *scanner) ListProbeModules() ([]string, error) {
+	return s.ListProbeModulesContext(s.ctx)
 }
 
-func (s *scanner) GetResults() []map[string]interface{} {
-	return s.asyncResults
-}
+// ListProbeModulesContext is ListProbeModules with a context. Zmap is killed when ctx is done.
+func (s *scanner) ListProbeModulesContext(ctx context.Context) ([]string, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
 
-func (s *scanner) ListProbeModules() ([]string, error) {
-	returnResults, err := exec.Command(s.binaryPath, "--list-probe-modules").Output()
+	returnResults, err := s.discover(ctx, "--list-probe-modules")
 	if err != nil {
 		return nil, err
 	}
@@ -483,7 +878,15 @@ func (s *scanner) ListProbeModules() ([]string, error) {
 }
 
 func (s *scanner) ListOutputModules() ([]string, error) {
-	returnResults, err := exec.Command(s.binaryPath, "--list-output-modules").Output()
+	return s.ListOutputModulesContext(s.ctx)
+}
+
+// ListOutputModulesContext is ListOutputModules with a context. Zmap is killed when ctx is done.
+func (s *scanner) ListOutputModulesContext(ctx context.Context) ([]string, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	returnResults, err := s.discover(ctx, "--list-output-modules")
 	if err != nil {
 		return nil, err
 	}
@@ -497,8 +900,34 @@ func (s *scanner) ListOutputModules() ([]string, error) {
 	return results, nil
 }
 
+// ListOutputFields returns the output fields of the probe module of the scanner.
+// The fields of the default probe module are returned if WithProbeModule is not passed.
 func (s *scanner) ListOutputFields() ([]OutputField, error) {
-	returnResults, err := exec.Command(s.binaryPath, "--list-output-fields").Output()
+	return s.ListOutputFieldsContext(s.ctx)
+}
+
+// ListOutputFieldsContext is ListOutputFields with a context. Zmap is killed when ctx is done.
+func (s *scanner) ListOutputFieldsContext(ctx context.Context) ([]OutputField, error) {
+	probeModule, _ := s.effectiveArguments().value("--probe-module")
+	return s.ListOutputFieldsForContext(ctx, probeModule)
+}
+
+// ListOutputFieldsFor returns the output fields of the given probe module. Ex: "icmp_echoscan"
+// The fields of the default probe module are returned if probeModule is empty.
+func (s *scanner) ListOutputFieldsFor(probeModule string) ([]OutputField, error) {
+	return s.ListOutputFieldsForContext(s.ctx, probeModule)
+}
+
+// ListOutputFieldsForContext is ListOutputFieldsFor with a context. Zmap is killed when ctx is done.
+func (s *scanner) ListOutputFieldsForContext(ctx context.Context, probeModule string) ([]OutputField, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	args := []string{"--list-output-fields"}
+	if probeModule != "" {
+		args = []string{"--probe-module", probeModule, "--list-output-fields"}
+	}
+	returnResults, err := s.discover(ctx, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -509,7 +938,8 @@ func (s *scanner) ListOutputFields() ([]OutputField, error) {
 			lineSplitted := strings.Fields(line)
 			var result OutputField
 			result.Name = lineSplitted[0]
-			result.Type = lineSplitted[1]
+			// Type is printed with a trailing colon. Ex: "int:"
+			result.Type = strings.TrimSuffix(lineSplitted[1], ":")
 			for i := 2; i < len(lineSplitted); i++ {
 				result.Explanation += fmt.Sprintf("%s ", lineSplitted[i])
 			}
@@ -520,8 +950,38 @@ func (s *scanner) ListOutputFields() ([]OutputField, error) {
 	return results, nil
 }
 
+// allOutputFields returns the output fields of every available probe module, without duplicates.
+func (s *scanner) allOutputFields() ([]OutputField, error) {
+	probeModules, err := s.ListProbeModules()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []OutputField
+	for _, probeModule := range probeModules {
+		fields, err := s.ListOutputFieldsFor(probeModule)
+		if err != nil {
+			return nil, err
+		}
+		for _, field := range fields {
+			if !outputFieldExists(results, field.Name) {
+				results = append(results, field)
+			}
+		}
+	}
+	return results, nil
+}
+
 func (s *scanner) GetVersion() (string, error) {
-	returnResult, err := exec.Command(s.binaryPath, "--version").Output()
+	return s.GetVersionContext(s.ctx)
+}
+
+// GetVersionContext is GetVersion with a context. Zmap is killed when ctx is done.
+func (s *scanner) GetVersionContext(ctx context.Context) (string, error) {
+	ctx, cancel := s.callContext(ctx)
+	defer cancel()
+
+	returnResult, err := s.discover(ctx, "--version")
 	if err != nil {
 		return "", err
 	}
@@ -542,47 +1002,7 @@ func (s *scanner) GetVersion() (string, error) {
 	return strings.Join(newVersionSlice, " "), nil
 }
 
-func (s *scanner) parseLogs(ioReader io.Reader) (traces []LogLine, debugs []LogLine, warnings []LogLine, infos []LogLine, fatals []LogLine, err error) {
-	sc := bufio.NewScanner(ioReader)
-	for sc.Scan() {
-		line := sc.Text()
-		switch {
-		case strings.Contains(line, "[TRACE]"):
-			logLine, err := s.parseLogLine(line)
-			if err != nil {
-				return traces, debugs, warnings, infos, fatals, err
-			}
-			traces = append(traces, logLine)
-		case strings.Contains(line, "[DEBUG]"):
-			logLine, err := s.parseLogLine(line)
-			if err != nil {
-				return traces, debugs, warnings, infos, fatals, err
-			}
-			debugs = append(debugs, logLine)
-		case strings.Contains(line, "[WARN]"):
-			logLine, err := s.parseLogLine(line)
-			if err != nil {
-				return traces, debugs, warnings, infos, fatals, err
-			}
-			warnings = append(warnings, logLine)
-		case strings.Contains(line, "[INFO]"):
-			logLine, err := s.parseLogLine(line)
-			if err != nil {
-				return traces, debugs, warnings, infos, fatals, err
-			}
-			infos = append(infos, logLine)
-		case strings.Contains(line, "[FATAL]"):
-			logLine, err := s.parseLogLine(line)
-			if err != nil {
-				return traces, debugs, warnings, infos, fatals, err
-			}
-			fatals = append(fatals, logLine)
-		}
-	}
-	return traces, debugs, warnings, infos, fatals, nil
-}
-
-func (s *scanner) parseLogLine(line string) (LogLine, error) {
+func parseLogLine(line string) (LogLine, error) {
 	logTimeLayout := "Jan 02 15:04:05.000"
 	logSplitted := strings.Split(line, " ")
 
@@ -602,10 +1022,22 @@ func (s *scanner) parseLogLine(line string) (LogLine, error) {
 }
 
 func (s *scanner) parseCsvOutputFile(ioReader io.Reader) ([]map[string]interface{}, error) {
-	reader := csv.NewReader(ioReader)
-
 	var rows []map[string]interface{}
-	var header []string
+	err := s.parseCsvStream(ioReader, nil, func(result map[string]interface{}) error {
+		rows = append(rows, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// parseCsvStream reads csv records one by one and passes them to handler.
+// If header is nil, first record is used as header.
+func (s *scanner) parseCsvStream(ioReader io.Reader, header []string, handler ResultHandler) error {
+	reader := csv.NewReader(ioReader)
+	reader.ReuseRecord = true
 
 	for {
 		record, err := reader.Read()
@@ -613,39 +1045,30 @@ func (s *scanner) parseCsvOutputFile(ioReader io.Reader) ([]map[string]interface
 			break
 		}
 		if err != nil {
-			return nil, err
+			return err
 		}
 		if header == nil {
-			header = record
-		} else {
-			dict := map[string]interface{}{}
-			for i := range header {
+			header = append([]string(nil), record...)
+			continue
+		}
+		dict := map[string]interface{}{}
+		for i := range header {
+			if i < len(record) {
 				dict[header[i]] = record[i]
 			}
-			rows = append(rows, dict)
+		}
+		if err := handler(dict); err != nil {
+			return err
 		}
 	}
-	return rows, nil
+	return nil
 }
 
+// getArgument returns the value of argument. Empty string is returned for flags without value.
 func (s *scanner) getArgument(argument string) (string, error) {
-	var (
-		argumentValue string
-	)
-
-	for index, arg := range s.args {
-		if arg == argument {
-			if (index + 1) < len(s.args) {
-				argumentValue = s.args[index+1]
-				if strings.Contains(argumentValue, "--") {
-					return "", nil
-				} else {
-					return argumentValue, nil
-				}
-			} else {
-				return "", nil
-			}
-		}
+	value, ok := s.args.value(argument)
+	if !ok {
+		return "", errors.New("argument not found")
 	}
-	return "", errors.New("argument not found")
+	return value, nil
 }
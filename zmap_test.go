@@ -3,11 +3,16 @@ package zmapgo
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/justmumu/zmapgo/zmaptest"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,12 +39,7 @@ func TestNewBlockingScanner_ZmapNotInstalled(t *testing.T) {
 
 func TestBlockingScanner_NormalBehavior(t *testing.T) {
 	t.Log("Testing NewBlockingScanner function under normal behavior")
-	_, err := exec.LookPath("zmap")
-	if err != nil {
-		panic("zmap is required to run this test")
-	}
-
-	scanner, err := NewBlockingScanner()
+	scanner, err := NewBlockingScanner(WithBinaryPath(fakeZmapPath(t)))
 	t.Logf("Returned Error: %v", err)
 	if err != nil {
 		t.Error("Expected that error is not returned while under normal behavior")
@@ -85,12 +85,7 @@ func TestAsyncScanner_WrongBinaryPath(t *testing.T) {
 
 func TestAsyncScanner_NormalBehavior(t *testing.T) {
 	t.Log("Testing NewAsyncScanner function under normal behavior")
-	_, err := exec.LookPath("zmap")
-	if err != nil {
-		panic("zmap is required to run this test")
-	}
-
-	scanner, err := NewAsyncScanner()
+	scanner, err := NewAsyncScanner(WithBinaryPath(fakeZmapPath(t)))
 	t.Logf("Returned Error: %v", err)
 	if err != nil {
 		t.Error("Expected that error is not returned while under normal behavior")
@@ -103,6 +98,11 @@ func TestAsyncScanner_NormalBehavior(t *testing.T) {
 }
 
 func TestRunBlocking(t *testing.T) {
+	// The test checks the log levels and the blacklist of the real zmap. Scans are tested with zmaptest in other tests.
+	if _, err := exec.LookPath("zmap"); err != nil {
+		t.Skip("zmap is required to run those tests")
+	}
+
 	testLogFilePath := "/tmp/test-log-file.txt"
 	testLogDirectoryPath := "/tmp/test-log-directory"
 	testOutputFilePath := "/tmp/test-output-file.txt"
@@ -114,10 +114,6 @@ func TestRunBlocking(t *testing.T) {
 		}
 	}
 
-	if _, err := exec.LookPath("zmap"); err != nil {
-		panic("zmap is required to run those tests")
-	}
-
 	tests := []struct {
 		testDesc    string
 		initOptions []InitOption
@@ -420,7 +416,7 @@ func TestRunBlocking(t *testing.T) {
 				WithDryrun(),
 			},
 			testTimeout:        false,
-			isErrorExpected:    false,
+			isErrorExpected:    true,
 			isTracesExpected:   true,
 			isDebugsExpected:   true,
 			isWarningsExpected: true,
@@ -533,8 +529,9 @@ func TestRunBlocking(t *testing.T) {
 }
 
 func TestRunAsync(t *testing.T) {
+	// The test checks the log levels of the real zmap. Async scans are tested with zmaptest in other tests.
 	if _, err := exec.LookPath("zmap"); err != nil {
-		panic("zmap is required to run those tests")
+		t.Skip("zmap is required to run those tests")
 	}
 
 	scanner, err := NewAsyncScanner()
@@ -554,51 +551,55 @@ func TestRunAsync(t *testing.T) {
 		return
 	}
 
-	if err := scanner.RunAsync(); err != nil {
+	job, err := scanner.RunAsync()
+	if err != nil {
 		t.Error("Expected that error is not returned while running RunAsync function")
 		return
 	}
 
 	// Block until finished
-	if err := scanner.Wait(); err != nil {
+	result, err := job.Wait()
+	if err != nil {
 		t.Error("Expected that error is not returned while waiting AsyncScanner finished")
 	}
+	if result == nil {
+		t.Fatal("Expected that scan result is returned")
+	}
+
+	if job.Status() != JobStatusSucceeded {
+		t.Error("Expected that job is succeeded")
+	}
 
-	traces := scanner.GetTraceMessages()
-	if len(traces) == 0 {
+	if len(result.Traces) == 0 {
 		t.Error("Expected that trace messages returned even under dryrun")
 	}
 
-	debugs := scanner.GetDebugMessages()
-	if len(debugs) == 0 {
+	if len(result.Debugs) == 0 {
 		t.Error("Expected that debugs messages returned even under dryrun")
 	}
 
-	warnings := scanner.GetWarningMessages()
-	if len(warnings) == 0 {
+	if len(result.Warnings) == 0 {
 		t.Error("Expected that warnings messages returned even under dryrun")
 	}
 
-	infos := scanner.GetInfoMessages()
-	if len(infos) == 0 {
+	if len(result.Infos) == 0 {
 		t.Error("Expected that infos messages returned even under dryrun")
 	}
 
-	fatals := scanner.GetFatalMessages()
-	if len(fatals) != 0 {
+	if len(result.Fatals) != 0 {
 		t.Error("Expected that fatals messages are not returned even under dryrun")
 	}
 
-	results := scanner.GetResults()
-	if len(results) != 0 {
+	if len(result.Results) != 0 {
 		t.Error("Expected that results are not returned because of dryrun")
 	}
 }
 
 func TestBlockingScanner_GetVersion(t *testing.T) {
-	scanner, err := NewBlockingScanner()
+	binary := zmaptest.New(t, zmaptest.Config{Version: "3.0.0"})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
 	if err != nil {
-		t.Error("Expected that error is not returned while creating BlockingScanner with NewBlockingScanner")
+		t.Fatal("Expected that error is not returned while creating BlockingScanner with NewBlockingScanner")
 	}
 
 	version, err := scanner.GetVersion()
@@ -606,7 +607,686 @@ func TestBlockingScanner_GetVersion(t *testing.T) {
 		t.Error("Expected that error is not returned while getting version")
 	}
 
-	if version == "" {
-		t.Error("Expected that version is returned.")
+	if version != "3.0.0" {
+		t.Errorf("Expected that version 3.0.0 is returned. But, got %s", version)
+	}
+}
+
+func TestParseCsvStream(t *testing.T) {
+	s := &scanner{}
+
+	tests := []struct {
+		testDesc        string
+		input           string
+		header          []string
+		handlerErr      error
+		expectedResult  []map[string]interface{}
+		isErrorExpected bool
+	}{
+		{
+			testDesc: "With Header Row",
+			input:    "saddr,sport\n1.1.1.1,80\n1.1.1.2,80\n",
+			expectedResult: []map[string]interface{}{
+				{"saddr": "1.1.1.1", "sport": "80"},
+				{"saddr": "1.1.1.2", "sport": "80"},
+			},
+		},
+		{
+			testDesc: "With Given Header",
+			input:    "1.1.1.1\n1.1.1.2\n",
+			header:   []string{"saddr"},
+			expectedResult: []map[string]interface{}{
+				{"saddr": "1.1.1.1"},
+				{"saddr": "1.1.1.2"},
+			},
+		},
+		{
+			testDesc: "With Only Header Row",
+			input:    "saddr,sport\n",
+		},
+		{
+			testDesc:        "With Handler Error",
+			input:           "saddr,sport\n1.1.1.1,80\n1.1.1.2,80\n",
+			handlerErr:      errors.New("stop"),
+			isErrorExpected: true,
+			expectedResult: []map[string]interface{}{
+				{"saddr": "1.1.1.1", "sport": "80"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			var results []map[string]interface{}
+			err := s.parseCsvStream(strings.NewReader(test.input), test.header, func(result map[string]interface{}) error {
+				results = append(results, result)
+				return test.handlerErr
+			})
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+			assert.Equal(t, test.expectedResult, results)
+		})
+	}
+}
+
+func TestRun_FakeBinary(t *testing.T) {
+	t.Log("Testing Run function with fake zmap binary")
+	results := zmaptest.CSV(
+		[]string{"saddr", "sport", "success"},
+		[]string{"1.1.1.1", "80", "1"},
+		[]string{"1.1.1.2", "80", "1"},
+	)
+	logs := []string{
+		zmaptest.LogLine("DEBUG", "zmap: sending"),
+		zmaptest.LogLine("INFO", "zmap: completed"),
+	}
+	metadata := `{"total_sent": 512, "probe_module": "tcp_synscan"}`
+
+	tests := []struct {
+		testDesc         string
+		config           zmaptest.Config
+		options          func(dir string) []Option
+		expectedResults  int
+		expectedInfos    int
+		expectedExitCode int
+		isErrorExpected  bool
+	}{
+		{
+			testDesc: "Results From Stdout",
+			config:   zmaptest.Config{Results: results, Logs: logs, Metadata: metadata},
+			options: func(dir string) []Option {
+				return nil
+			},
+			expectedResults: 2,
+			expectedInfos:   1,
+		},
+		{
+			testDesc: "Results And Logs From Files",
+			config:   zmaptest.Config{Results: results, Logs: logs, Metadata: metadata},
+			options: func(dir string) []Option {
+				return []Option{
+					WithOutputFile(filepath.Join(dir, "output.csv")),
+					WithLogFile(filepath.Join(dir, "zmap.log")),
+				}
+			},
+			expectedResults: 2,
+			expectedInfos:   1,
+		},
+		{
+			testDesc: "With Non Zero Exit Code",
+			config:   zmaptest.Config{Logs: logs, ExitCode: 1},
+			options: func(dir string) []Option {
+				return nil
+			},
+			expectedInfos:    1,
+			expectedExitCode: 1,
+			isErrorExpected:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, test.config)
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			if err := scanner.AddOptions(append([]Option{WithTargetPort("80")}, test.options(t.TempDir())...)...); err != nil {
+				t.Fatalf("Expected that error is not returned while adding options: %v", err)
+			}
+
+			result, err := scanner.Run(context.Background())
+			t.Logf("Returned Error: %v", err)
+			assert.Equal(t, test.isErrorExpected, err != nil)
+			if !assert.NotNil(t, result) {
+				return
+			}
+			assert.Len(t, result.Results, test.expectedResults)
+			assert.Len(t, result.Infos, test.expectedInfos)
+			assert.Equal(t, test.expectedExitCode, result.ExitCode)
+			assert.Equal(t, binary.LastInvocation(), result.CommandLine[1:])
+		})
+	}
+}
+
+func TestRunStream_NilHandler(t *testing.T) {
+	t.Log("Testing RunStream function with nil handler")
+	binary := zmaptest.New(t, zmaptest.Config{Results: zmaptest.CSV([]string{"saddr"}, []string{"1.1.1.1"})})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+	invocations := len(binary.Invocations())
+
+	_, err = scanner.RunStream(context.Background(), nil)
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for nil handler")
+	}
+	assert.Len(t, binary.Invocations(), invocations, "Expected that zmap is not launched")
+}
+
+func TestRunStream_FakeBinary(t *testing.T) {
+	t.Log("Testing RunStream function with fake zmap binary")
+	binary := zmaptest.New(t, zmaptest.Config{
+		Results:  zmaptest.CSV([]string{"saddr", "sport"}, []string{"1.1.1.1", "80"}, []string{"1.1.1.2", "443"}),
+		Metadata: `{"total_sent": 512}`,
+	})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	if err := scanner.AddOptions(WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	var saddrs []interface{}
+	result, err := scanner.RunStream(context.Background(), func(result map[string]interface{}) error {
+		saddrs = append(saddrs, result["saddr"])
+		return nil
+	})
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned")
+	}
+	assert.Equal(t, []interface{}{"1.1.1.1", "1.1.1.2"}, saddrs)
+	if assert.NotNil(t, result) && assert.NotNil(t, result.Metadata) {
+		assert.Equal(t, uint64(512), result.Metadata.PacketsSent)
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	t.Log("Testing Run function with cancelled context")
+	results := zmaptest.CSV([]string{"saddr", "sport"}, []string{"1.1.1.1", "80"}, []string{"1.1.1.2", "80"})
+	logs := []string{zmaptest.LogLine("INFO", "zmap: started")}
+
+	tests := []struct {
+		testDesc         string
+		config           zmaptest.Config
+		gracePeriod      time.Duration
+		options          func(dir string) []Option
+		expectedExitCode int
+	}{
+		{
+			testDesc:    "Stopped With SIGINT",
+			config:      zmaptest.Config{Results: results, Logs: logs, Delay: time.Minute},
+			gracePeriod: time.Minute,
+			options: func(dir string) []Option {
+				return nil
+			},
+			expectedExitCode: 130,
+		},
+		{
+			testDesc:    "Stopped With SIGINT And Output File",
+			config:      zmaptest.Config{Results: results, Logs: logs, Delay: time.Minute},
+			gracePeriod: time.Minute,
+			options: func(dir string) []Option {
+				return []Option{WithOutputFile(filepath.Join(dir, "output.csv"))}
+			},
+			expectedExitCode: 130,
+		},
+		{
+			testDesc:    "Killed After Grace Period",
+			config:      zmaptest.Config{Results: results, Logs: logs, Delay: time.Minute, IgnoreInterrupt: true},
+			gracePeriod: 100 * time.Millisecond,
+			options: func(dir string) []Option {
+				return nil
+			},
+			expectedExitCode: -1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, test.config)
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path), WithGracePeriod(test.gracePeriod))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			if err := scanner.AddOptions(append([]Option{WithTargetPort("80")}, test.options(t.TempDir())...)...); err != nil {
+				t.Fatalf("Expected that error is not returned while adding options: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			result, err := scanner.Run(ctx)
+			t.Logf("Returned Error: %v", err)
+			if !errors.Is(err, ErrScanTimeout) {
+				t.Error("Expected that returned error wraps ErrScanTimeout")
+			}
+			if !assert.NotNil(t, result) {
+				return
+			}
+			assert.Len(t, result.Results, 2)
+			assert.Len(t, result.Infos, 1)
+			assert.Equal(t, test.expectedExitCode, result.ExitCode)
+		})
+	}
+}
+
+func TestRun_Targets(t *testing.T) {
+	t.Log("Testing Run function passes targets as arguments or a temporary whitelist file")
+	manyTargets := make([]string, 0, maxTargetArgs+1)
+	for i := 0; i <= maxTargetArgs; i++ {
+		manyTargets = append(manyTargets, fmt.Sprintf("10.%d.%d.1", i/256, i%256))
+	}
+
+	tests := []struct {
+		testDesc              string
+		targets               []string
+		isTargetsFileExpected bool
+	}{
+		{
+			testDesc: "With Few Targets",
+			targets:  []string{"192.168.1.1", "192.168.2.0/24"},
+		},
+		{
+			testDesc:              "With Many Targets",
+			targets:               manyTargets,
+			isTargetsFileExpected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			if err := scanner.AddOptions(WithTargets(test.targets...), WithTargetPort("80")); err != nil {
+				t.Fatalf("Expected that error is not returned while adding targets: %v", err)
+			}
+
+			_, err = scanner.Run(context.Background())
+			t.Logf("Returned Error: %v", err)
+			if err != nil {
+				t.Error("Expected that error is not returned")
+			}
+
+			args := binary.LastInvocation()
+			targetsFile, ok := getArgumentValue(args, "--whitelist-file")
+			assert.Equal(t, test.isTargetsFileExpected, ok)
+			if test.isTargetsFileExpected {
+				assert.NotContains(t, args, test.targets[0])
+				if _, err := os.Stat(targetsFile); !errors.Is(err, os.ErrNotExist) {
+					t.Error("Expected that temporary targets file is removed after the run")
+				}
+			} else {
+				assert.Equal(t, test.targets, args[len(args)-len(test.targets):])
+			}
+		})
+	}
+}
+
+func TestRun_IPv6Targets(t *testing.T) {
+	t.Log("Testing Run function passes ipv6 targets with a temporary ipv6 target file")
+	binary := zmaptest.New(t, zmaptest.Config{
+		Version:      "4.1.0",
+		ProbeModules: []string{"tcp_synscan", ProbeModuleIPv6TCPSynScan},
+	})
+	s, err := newScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	err = s.AddOptions(
+		WithTargets("2001:db8::1", "2001:db8::2"),
+		WithIPv6SourceIP("2001:db8::100"),
+		WithProbeModule(ProbeModuleIPv6TCPSynScan),
+		WithTargetPort("80"),
+	)
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	cfg, err := s.prepareRun(context.Background())
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while preparing run: %v", err)
+	}
+	targetsFile, ok := getArgumentValue(cfg.extraArgs, "--ipv6-target-file")
+	if !ok {
+		t.Fatal("Expected that ipv6 target file is passed")
+	}
+	content, err := ioutil.ReadFile(targetsFile)
+	if err != nil {
+		t.Fatalf("Expected that ipv6 target file is readable: %v", err)
+	}
+	assert.Equal(t, "2001:db8::1\n2001:db8::2\n", string(content))
+	assert.NotContains(t, cfg.extraArgs, "2001:db8::1")
+	cfg.cleanup()
+
+	_, err = s.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned")
+	}
+	targetsFile, ok = getArgumentValue(binary.LastInvocation(), "--ipv6-target-file")
+	assert.True(t, ok, "Expected that ipv6 target file is passed to zmap")
+	if _, err := os.Stat(targetsFile); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected that temporary ipv6 target file is removed after the run")
+	}
+
+	s.runner = NewPrefixRunner("env", "ZMAPGO_TEST=1")
+	s.localRunner = false
+	_, err = s.prepareRun(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err == nil {
+		t.Error("Expected that error is returned for ipv6 targets with a custom runner")
+	}
+}
+
+func TestRunConfig_WriteTempFile(t *testing.T) {
+	t.Log("Testing writeTempFile function writes targets and cleanup removes them")
+	cfg := &runConfig{}
+	path, err := cfg.writeTempFile("zmapgo-targets-*.txt", targetsWriter{"192.168.1.1", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("Expected that error is not returned: %v", err)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected that targets file is readable: %v", err)
+	}
+	assert.Equal(t, "192.168.1.1\n10.0.0.0/8\n", string(content))
+
+	cfg.cleanup()
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Expected that temporary file is removed by cleanup")
+	}
+}
+
+func TestRun_DryRunPackets(t *testing.T) {
+	t.Log("Testing Run function parses the packets printed in dryrun mode")
+	binary := zmaptest.New(t, zmaptest.Config{
+		Results: zmaptest.CSV([]string{"saddr"}, []string{"1.1.1.1"}),
+		DryRun:  dryRunOutput,
+	})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.0/30"), WithTargetPort("80"), WithDryrun()); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+
+	assert.Empty(t, result.Results)
+	if assert.Len(t, result.DryRunPackets, 2) {
+		assert.Equal(t, "1.1.1.2", result.DryRunPackets[1].Daddr.String())
+		assert.Equal(t, uint16(443), result.DryRunPackets[1].Dport)
+	}
+}
+
+func TestRun_Rerun(t *testing.T) {
+	t.Log("Testing Run function does not change scanner arguments, so the scanner can be run again")
+	binary := zmaptest.New(t, zmaptest.Config{Results: zmaptest.CSV([]string{"saddr"}, []string{"1.1.1.1"})})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.1"), WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+	options := scanner.Options()
+
+	var invocations [][]string
+	for i := 0; i < 2; i++ {
+		result, err := scanner.Run(context.Background())
+		t.Logf("Returned Error: %v", err)
+		if err != nil {
+			t.Fatal("Expected that error is not returned")
+		}
+		assert.Len(t, result.Results, 1, "Expected that results of previous runs are not kept")
+		invocations = append(invocations, binary.LastInvocation())
+	}
+
+	assert.Equal(t, options, scanner.Options())
+	// Temporary metadata file is different on every run.
+	for i := range invocations {
+		args := parseArguments(invocations[i])
+		args.remove("--metadata-file")
+		invocations[i] = args.strings()
+	}
+	assert.Equal(t, invocations[0], invocations[1])
+
+	err = scanner.AddOptions(WithVerbosity(VerbosityLevel3), WithOutputFields([]string{"saddr"}))
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that verbosity and output fields can be added after a run")
+	}
+}
+
+func TestRunBlockingContext(t *testing.T) {
+	t.Log("Testing RunBlockingContext function with a timeout, and running the same scanner again")
+	binary := zmaptest.New(t, zmaptest.Config{
+		Results: zmaptest.CSV([]string{"saddr"}, []string{"1.1.1.1"}),
+		Delay:   time.Second,
+	})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path), WithGracePeriod(time.Second))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.1"), WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, _, _, _, _, _, err = scanner.RunBlockingContext(ctx)
+	t.Logf("Returned Error: %v", err)
+	if !errors.Is(err, ErrScanTimeout) {
+		t.Error("Expected that ErrScanTimeout is returned when the context of the run is done")
+	}
+
+	results, _, _, _, _, _, err := scanner.RunBlockingContext(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned while running again with another context")
+	}
+	assert.Len(t, results, 1)
+}
+
+func TestListOutputFieldsFor(t *testing.T) {
+	t.Log("Testing ListOutputFieldsFor function lists the output fields of the given probe module")
+	binary := zmaptest.New(t, zmaptest.Config{ModuleOutputFields: icmpOutputFields})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+
+	fields, err := scanner.ListOutputFieldsFor("icmp_echoscan")
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Error("Expected that error is not returned")
+	}
+	assert.Len(t, fields, len(icmpOutputFields["icmp_echoscan"]))
+	assert.Equal(t, []string{"--probe-module", "icmp_echoscan", "--list-output-fields"}, binary.LastInvocation())
+
+	fields, err = scanner.ListOutputFieldsFor("")
+	if err != nil {
+		t.Error("Expected that error is not returned for default probe module")
+	}
+	assert.Len(t, fields, len(zmaptest.DefaultOutputFields))
+
+	// ListOutputFields follows the probe module of the scanner
+	if err := scanner.AddOptions(WithProbeModule("icmp_echoscan")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding probe module: %v", err)
+	}
+	invocations := len(binary.Invocations())
+	fields, err = scanner.ListOutputFields()
+	if err != nil {
+		t.Error("Expected that error is not returned for probe module of scanner")
+	}
+	assert.Len(t, fields, len(icmpOutputFields["icmp_echoscan"]))
+	assert.Len(t, binary.Invocations(), invocations, "Expected that output fields of probe module are cached")
+}
+
+func TestRun_ProbeModuleOutputFields(t *testing.T) {
+	t.Log("Testing Run function selects the output fields of the probe module by default")
+	binary := zmaptest.New(t, zmaptest.Config{
+		ModuleOutputFields: icmpOutputFields,
+		Results:            zmaptest.CSV([]string{"saddr", "type"}, []string{"1.1.1.1", "0"}),
+	})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargets("1.1.1.1"), WithProbeModule("icmp_echoscan")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	_, err = scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+	outputFields, _ := getArgumentValue(binary.LastInvocation(), "--output-fields")
+	assert.Equal(t, "saddr,daddr,type,code,data,success", outputFields)
+}
+
+func TestRun_UserMetadataFile(t *testing.T) {
+	t.Log("Testing Run function uses the metadata file passed as a short flag or in the config file")
+	dir := t.TempDir()
+	metadataFile := filepath.Join(dir, "metadata.json")
+	configFile := filepath.Join(dir, "zmap.conf")
+	if err := ioutil.WriteFile(configFile, []byte("metadata-file "+metadataFile+"\n"), 0644); err != nil {
+		t.Fatalf("Expected that config file is written: %v", err)
+	}
+
+	tests := []struct {
+		testDesc        string
+		options         []Option
+		metadataWritten bool
+	}{
+		{
+			testDesc: "With Short Flag",
+			options:  []Option{WithCustomArguments("-m", metadataFile)},
+			// Fake binary writes the metadata to the file passed with -m
+			metadataWritten: true,
+		},
+		{
+			testDesc: "With Config File",
+			options:  []Option{WithConfigFile(configFile)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			binary := zmaptest.New(t, zmaptest.Config{Metadata: `{"total_sent": 512}`})
+			scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+			if err != nil {
+				t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+			}
+			if err := scanner.AddOptions(append([]Option{WithTargetPort("80")}, test.options...)...); err != nil {
+				t.Fatalf("Expected that error is not returned while adding options: %v", err)
+			}
+
+			result, err := scanner.Run(context.Background())
+			t.Logf("Returned Error: %v", err)
+			if err != nil {
+				t.Fatal("Expected that error is not returned")
+			}
+			if test.metadataWritten && assert.NotNil(t, result.Metadata) {
+				assert.Equal(t, uint64(512), result.Metadata.PacketsSent)
+			}
+			// Metadata file of the config file is not passed on the command line, since it would override it.
+			metadataFiles := filterArguments(parseArguments(binary.LastInvocation()).longFlags(), "--metadata-file")
+			assert.Equal(t, test.metadataWritten, len(metadataFiles) == 1, "Expected that temporary metadata file is not passed")
+			for _, argument := range metadataFiles {
+				assert.Equal(t, metadataFile, argument.Value)
+			}
+		})
+	}
+}
+
+func TestRun_ShortFlagDefaults(t *testing.T) {
+	t.Log("Testing Run function does not add defaults for arguments passed as short flags")
+	// Zmap does not write csv header for one output field
+	binary := zmaptest.New(t, zmaptest.Config{Results: "1.1.1.1\n"})
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	outputFile := filepath.Join(t.TempDir(), "output.csv")
+	err = scanner.AddOptions(WithTargetPort("80"), WithCustomArguments("-v", "3", "-f", "saddr", "-o", outputFile))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+	assert.Equal(t, []map[string]interface{}{{"saddr": "1.1.1.1"}}, result.Results, "Expected that results are read from output file")
+
+	args := parseArguments(binary.LastInvocation())
+	for _, flag := range []string{"-v", "-f", "-o"} {
+		assert.False(t, args.has(flag), "Expected that %s is passed with its long name", flag)
+	}
+	assert.Equal(t, arguments{{Flag: "--verbosity", Value: "3", HasValue: true}}, filterArguments(args, "--verbosity"))
+	assert.Equal(t, arguments{{Flag: "--output-fields", Value: "saddr", HasValue: true}}, filterArguments(args, "--output-fields"))
+}
+
+// filterArguments returns the arguments with flag.
+func filterArguments(args arguments, flag string) arguments {
+	var filtered arguments
+	for _, argument := range args {
+		if argument.Flag == flag {
+			filtered = append(filtered, argument)
+		}
+	}
+	return filtered
+}
+
+func TestGetScanMetadata(t *testing.T) {
+	t.Log("Testing GetScanMetadata function is only changed by RunBlocking and RunAsync")
+	config := zmaptest.Config{Metadata: `{"total_sent": 512}`}
+	binary := zmaptest.New(t, config)
+	scanner, err := NewBlockingScanner(WithBinaryPath(binary.Path))
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while creating scanner with fake binary: %v", err)
+	}
+	if err := scanner.AddOptions(WithTargetPort("80")); err != nil {
+		t.Fatalf("Expected that error is not returned while adding options: %v", err)
+	}
+
+	result, err := scanner.Run(context.Background())
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+	assert.NotNil(t, result.Metadata)
+	assert.Nil(t, scanner.GetScanMetadata(), "Expected that Run does not change scanner metadata")
+
+	_, _, _, _, _, _, err = scanner.RunBlocking()
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
+	}
+	if assert.NotNil(t, scanner.GetScanMetadata()) {
+		assert.Equal(t, uint64(512), scanner.GetScanMetadata().PacketsSent)
+	}
+
+	asyncScanner := newAsyncScannerWithFake(t, config)
+	job, err := asyncScanner.RunAsync()
+	if err != nil {
+		t.Fatalf("Expected that error is not returned while starting job: %v", err)
+	}
+	result, err = job.Wait()
+	t.Logf("Returned Error: %v", err)
+	if err != nil {
+		t.Fatal("Expected that error is not returned")
 	}
+	assert.Equal(t, result.Metadata, asyncScanner.GetScanMetadata())
 }
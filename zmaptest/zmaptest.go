@@ -0,0 +1,359 @@
+// Package zmaptest provides a scriptable fake zmap binary for hermetic tests.
+//
+// The fake binary answers --version, --list-probe-modules, --list-output-fields and
+// --list-output-modules, and emits canned results, log lines, status updates and metadata
+// for scans. It does not need privileges or network access, so it can be passed to
+// zmapgo.WithBinaryPath in unit tests.
+package zmaptest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultVersion is the version reported by the fake binary if Config.Version is empty.
+const DefaultVersion = "2.1.1"
+
+// invocationEnd separates the invocations recorded by the fake binary.
+const invocationEnd = "--zmaptest-end--"
+
+// OutputField is an output field reported by --list-output-fields.
+type OutputField struct {
+	Name        string
+	Type        string
+	Explanation string
+}
+
+var (
+	// DefaultProbeModules are reported by --list-probe-modules if Config.ProbeModules is nil.
+	DefaultProbeModules = []string{
+		"tcp_synscan",
+		"icmp_echoscan",
+		"icmp_echo_time",
+		"udp",
+		"ntp",
+		"upnp",
+	}
+
+	// DefaultOutputModules are reported by --list-output-modules if Config.OutputModules is nil.
+	DefaultOutputModules = []string{
+		"csv",
+		"json",
+	}
+
+	// DefaultOutputFields are reported by --list-output-fields if Config.OutputFields is nil.
+	// They are the fields of tcp_synscan probe module.
+	DefaultOutputFields = []OutputField{
+		{"saddr", "string", "source IP address of response"},
+		{"saddr_raw", "int", "network order integer form of source IP address"},
+		{"daddr", "string", "destination IP address of response"},
+		{"daddr_raw", "int", "network order integer form of destination IP address"},
+		{"ipid", "int", "IP identification number of response"},
+		{"ttl", "int", "time-to-live of response packet"},
+		{"sport", "int", "TCP source port"},
+		{"dport", "int", "TCP destination port"},
+		{"seqnum", "int", "TCP sequence number"},
+		{"acknum", "int", "TCP acknowledgement number"},
+		{"window", "int", "TCP window"},
+		{"classification", "string", "packet classification"},
+		{"success", "bool", "is response considered success"},
+		{"repeat", "bool", "Is response a repeat response from host"},
+		{"cooldown", "bool", "Was response received during the cooldown period"},
+		{"timestamp_str", "string", "timestamp of when response arrived in ISO8601 format."},
+		{"timestamp_ts", "int", "timestamp of when response arrived in seconds since Epoch"},
+		{"timestamp_us", "int", "microsecond part of timestamp (e.g. microseconds since 'timestamp-ts')"},
+	}
+)
+
+// Config describes how the fake binary behaves.
+type Config struct {
+	// Version is printed for --version. DefaultVersion is used if it is empty.
+	Version string
+
+	ProbeModules  []string
+	OutputModules []string
+	OutputFields  []OutputField
+	// ModuleOutputFields are reported by --list-output-fields if --probe-module is passed before it.
+	// OutputFields is reported for the probe modules that are not in the map.
+	ModuleOutputFields map[string][]OutputField
+
+	// Results is written to stdout, or to the file passed with --output-file, as is.
+	// CSV can be used to build it.
+	Results string
+	// DryRun is written to stdout instead of Results if --dryrun is passed.
+	DryRun string
+	// Logs are written to stderr, or to the file passed with --log-file or --log-directory.
+	// LogLine can be used to build them.
+	Logs []string
+	// StatusLines are written to stderr after Logs.
+	StatusLines []string
+	// StatusUpdates is written to the file passed with --status-updates-file as is.
+	StatusUpdates string
+	// Metadata is written to the file passed with --metadata-file. A minimal JSON object is used if it is empty.
+	Metadata string
+
+	// Delay is the time to wait before exiting, after everything is written.
+	// The fake binary exits with code 130 if it receives SIGINT while waiting.
+	Delay time.Duration
+	// IgnoreInterrupt makes the fake binary ignore SIGINT, like a zmap process that hangs while stopping.
+	IgnoreInterrupt bool
+	// ExitCode is the exit code of scans.
+	ExitCode int
+}
+
+// Binary is a fake zmap binary.
+type Binary struct {
+	// Path is the path of the fake binary.
+	Path string
+
+	dir string
+}
+
+// TB is the part of testing.TB that is used by New. *testing.T and *testing.B implement it.
+// It is declared here, so that importing the package does not pull testing into non-test binaries.
+type TB interface {
+	Helper()
+	TempDir() string
+	Fatalf(format string, args ...interface{})
+}
+
+// New writes a fake zmap binary into a temporary directory of tb.
+// The directory is removed when the test finishes.
+func New(tb TB, cfg Config) *Binary {
+	tb.Helper()
+
+	binary, err := Build(tb.TempDir(), cfg)
+	if err != nil {
+		tb.Fatalf("unable to build fake zmap binary: %v", err)
+	}
+	return binary
+}
+
+// Build writes a fake zmap binary and its data files into dir.
+func Build(dir string, cfg Config) (*Binary, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Version == "" {
+		cfg.Version = DefaultVersion
+	}
+	if cfg.ProbeModules == nil {
+		cfg.ProbeModules = DefaultProbeModules
+	}
+	if cfg.OutputModules == nil {
+		cfg.OutputModules = DefaultOutputModules
+	}
+	if cfg.OutputFields == nil {
+		cfg.OutputFields = DefaultOutputFields
+	}
+	if cfg.Metadata == "" {
+		cfg.Metadata = "{}"
+	}
+
+	files := map[string]string{
+		"version":        fmt.Sprintf("zmap %s\n", cfg.Version),
+		"probe-modules":  lines(cfg.ProbeModules),
+		"output-modules": lines(cfg.OutputModules),
+		"output-fields":  outputFieldLines(cfg.OutputFields),
+		"results":        cfg.Results,
+		"dryrun":         cfg.DryRun,
+		"logs":           lines(cfg.Logs),
+		"status":         lines(cfg.StatusLines),
+		"status-updates": cfg.StatusUpdates,
+		"metadata":       cfg.Metadata,
+		"delay":          strconv.FormatFloat(cfg.Delay.Seconds(), 'f', 3, 64),
+		"exit-code":      strconv.Itoa(cfg.ExitCode),
+		"ignore-int":     strconv.FormatBool(cfg.IgnoreInterrupt),
+		"invocations":    "",
+	}
+	for module, fields := range cfg.ModuleOutputFields {
+		files["output-fields-"+module] = outputFieldLines(fields)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			return nil, err
+		}
+	}
+
+	binary := &Binary{
+		Path: filepath.Join(dir, "zmap"),
+		dir:  dir,
+	}
+	script := strings.Replace(script, "{{DIR}}", shellQuote(dir), 1)
+	if err := ioutil.WriteFile(binary.Path, []byte(script), 0755); err != nil {
+		return nil, err
+	}
+	return binary, nil
+}
+
+// Invocations returns the arguments of every invocation of the fake binary, in order.
+func (b *Binary) Invocations() [][]string {
+	content, err := ioutil.ReadFile(filepath.Join(b.dir, "invocations"))
+	if err != nil {
+		return nil
+	}
+
+	var (
+		invocations [][]string
+		current     = []string{}
+	)
+	for _, line := range strings.Split(string(content), "\n") {
+		if line == invocationEnd {
+			invocations = append(invocations, current)
+			current = []string{}
+			continue
+		}
+		current = append(current, line)
+	}
+	return invocations
+}
+
+// LastInvocation returns the arguments of the last invocation of the fake binary.
+// It returns nil if the binary is not invoked.
+func (b *Binary) LastInvocation() []string {
+	invocations := b.Invocations()
+	if len(invocations) == 0 {
+		return nil
+	}
+	return invocations[len(invocations)-1]
+}
+
+// LogLine formats a log line like zmap does. Ex: "Dec 10 12:00:00.000 [INFO] zmap: started"
+func LogLine(level string, message string) string {
+	return fmt.Sprintf("%s [%s] %s", time.Now().Format("Jan 02 15:04:05.000"), level, message)
+}
+
+// CSV formats a header and rows like zmap csv output module does.
+func CSV(header []string, rows ...[]string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.Join(header, ","))
+	builder.WriteString("\n")
+	for _, row := range rows {
+		builder.WriteString(strings.Join(row, ","))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+func lines(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.Join(values, "\n") + "\n"
+}
+
+func outputFieldLines(fields []OutputField) string {
+	var builder strings.Builder
+	for _, field := range fields {
+		fmt.Fprintf(&builder, "%-15s %6s: %s\n", field.Name, field.Type, field.Explanation)
+	}
+	return builder.String()
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+// script is the fake zmap binary. {{DIR}} is replaced with the directory of data files.
+const script = `#!/bin/sh
+dir={{DIR}}
+
+{
+	for arg in "$@"; do
+		printf '%s\n' "$arg"
+	done
+	printf '%s\n' '` + invocationEnd + `'
+} >> "$dir/invocations"
+
+output_file=""
+log_file=""
+log_directory=""
+metadata_file=""
+status_updates_file=""
+probe_module=""
+dryrun=0
+
+while [ $# -gt 0 ]; do
+	case "$1" in
+	-V|--version)
+		cat "$dir/version"
+		exit 0
+		;;
+	--list-probe-modules)
+		cat "$dir/probe-modules"
+		exit 0
+		;;
+	--list-output-modules)
+		cat "$dir/output-modules"
+		exit 0
+		;;
+	--list-output-fields)
+		if [ -n "$probe_module" ] && [ -f "$dir/output-fields-$probe_module" ]; then
+			cat "$dir/output-fields-$probe_module"
+		else
+			cat "$dir/output-fields"
+		fi
+		exit 0
+		;;
+	-M|--probe-module) probe_module="$2"; shift ;;
+	--probe-module=*) probe_module="${1#*=}" ;;
+	-o|--output-file) output_file="$2"; shift ;;
+	--output-file=*) output_file="${1#*=}" ;;
+	-l|--log-file) log_file="$2"; shift ;;
+	--log-file=*) log_file="${1#*=}" ;;
+	-L|--log-directory) log_directory="$2"; shift ;;
+	--log-directory=*) log_directory="${1#*=}" ;;
+	-m|--metadata-file) metadata_file="$2"; shift ;;
+	--metadata-file=*) metadata_file="${1#*=}" ;;
+	-u|--status-updates-file) status_updates_file="$2"; shift ;;
+	--status-updates-file=*) status_updates_file="${1#*=}" ;;
+	-d|--dryrun) dryrun=1 ;;
+	esac
+	shift
+done
+
+if [ -n "$log_file" ]; then
+	cat "$dir/logs" > "$log_file"
+elif [ -n "$log_directory" ]; then
+	cat "$dir/logs" > "$log_directory/zmap-$(date +%Y-%m-%dT%H%M%S%z).log"
+else
+	cat "$dir/logs" >&2
+fi
+
+cat "$dir/status" >&2
+
+if [ -n "$status_updates_file" ]; then
+	cat "$dir/status-updates" > "$status_updates_file"
+fi
+
+if [ "$dryrun" = 1 ]; then
+	cat "$dir/dryrun"
+elif [ -n "$output_file" ] && [ "$output_file" != "-" ]; then
+	cat "$dir/results" > "$output_file"
+else
+	cat "$dir/results"
+fi
+
+if [ -n "$metadata_file" ]; then
+	cat "$dir/metadata" > "$metadata_file"
+fi
+
+# sleep runs in background so that signals are handled while waiting.
+# Its output is detached, otherwise it would keep the pipes of the caller open.
+sleep "$(cat "$dir/delay")" < /dev/null > /dev/null 2>&1 &
+sleep_pid=$!
+if [ "$(cat "$dir/ignore-int")" = true ]; then
+	trap '' INT
+else
+	trap 'kill "$sleep_pid" 2> /dev/null; exit 130' INT
+fi
+wait "$sleep_pid"
+
+exit "$(cat "$dir/exit-code")"
+`
@@ -0,0 +1,178 @@
+package zmaptest
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_Discovery(t *testing.T) {
+	t.Log("Testing fake binary with discovery arguments")
+	binary := New(t, Config{Version: "3.0.0", ProbeModules: []string{"tcp_synscan"}})
+
+	tests := []struct {
+		testDesc       string
+		args           []string
+		expectedOutput string
+	}{
+		{
+			testDesc:       "With Version",
+			args:           []string{"--version"},
+			expectedOutput: "zmap 3.0.0\n",
+		},
+		{
+			testDesc:       "With Probe Modules",
+			args:           []string{"--list-probe-modules"},
+			expectedOutput: "tcp_synscan\n",
+		},
+		{
+			testDesc:       "With Output Modules",
+			args:           []string{"--list-output-modules"},
+			expectedOutput: "csv\njson\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			out, err := exec.Command(binary.Path, test.args...).Output()
+			t.Logf("Returned Error: %v", err)
+			if err != nil {
+				t.Error("Expected that error is not returned")
+			}
+			assert.Equal(t, test.expectedOutput, string(out))
+		})
+	}
+
+	out, err := exec.Command(binary.Path, "--list-output-fields").Output()
+	if err != nil {
+		t.Error("Expected that error is not returned while listing output fields")
+	}
+	assert.Contains(t, string(out), "saddr           string: source IP address of response")
+}
+
+func TestNew_ModuleOutputFields(t *testing.T) {
+	t.Log("Testing fake binary lists output fields of the given probe module")
+	binary := New(t, Config{
+		ModuleOutputFields: map[string][]OutputField{
+			"icmp_echoscan": {{"icmp_type", "int", "icmp message type"}},
+		},
+	})
+
+	tests := []struct {
+		testDesc       string
+		args           []string
+		expectedOutput string
+	}{
+		{
+			testDesc:       "Probe module with output fields",
+			args:           []string{"--probe-module", "icmp_echoscan", "--list-output-fields"},
+			expectedOutput: "icmp_type          int: icmp message type\n",
+		},
+		{
+			testDesc:       "Probe module with output fields after equal sign",
+			args:           []string{"--probe-module=icmp_echoscan", "--list-output-fields"},
+			expectedOutput: "icmp_type          int: icmp message type\n",
+		},
+		{
+			testDesc:       "Probe module without output fields",
+			args:           []string{"--probe-module", "udp", "--list-output-fields"},
+			expectedOutput: outputFieldLines(DefaultOutputFields),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testDesc, func(t *testing.T) {
+			out, err := exec.Command(binary.Path, test.args...).Output()
+			t.Logf("Returned Error: %v", err)
+			if err != nil {
+				t.Error("Expected that error is not returned")
+			}
+			assert.Equal(t, test.expectedOutput, string(out))
+		})
+	}
+}
+
+func TestNew_Scan(t *testing.T) {
+	t.Log("Testing fake binary while scanning")
+	binary := New(t, Config{
+		Results:  CSV([]string{"saddr"}, []string{"1.1.1.1"}),
+		Logs:     []string{LogLine("INFO", "zmap: started")},
+		Metadata: `{"total_sent": 1}`,
+		ExitCode: 3,
+	})
+
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "output.csv")
+	logFile := filepath.Join(dir, "zmap.log")
+	metadataFile := filepath.Join(dir, "metadata.json")
+
+	err := exec.Command(binary.Path, "--output-file", outputFile, "--log-file="+logFile, "-m", metadataFile, "1.1.1.0/24").Run()
+	t.Logf("Returned Error: %v", err)
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 3 {
+		t.Error("Expected that fake binary exits with the configured exit code")
+	}
+
+	content, _ := ioutil.ReadFile(outputFile)
+	assert.Equal(t, "saddr\n1.1.1.1\n", string(content))
+	content, _ = ioutil.ReadFile(logFile)
+	assert.Contains(t, string(content), "[INFO] zmap: started")
+	content, _ = ioutil.ReadFile(metadataFile)
+	assert.Equal(t, `{"total_sent": 1}`, string(content))
+
+	assert.Equal(t, []string{"--output-file", outputFile, "--log-file=" + logFile, "-m", metadataFile, "1.1.1.0/24"}, binary.LastInvocation())
+}
+
+func TestNew_Invocations(t *testing.T) {
+	t.Log("Testing Invocations function with arguments containing spaces")
+	binary := New(t, Config{})
+
+	if binary.LastInvocation() != nil {
+		t.Error("Expected that no invocation is returned before the binary is run")
+	}
+
+	_ = exec.Command(binary.Path, "--version").Run()
+	_ = exec.Command(binary.Path, "--notes", "two words").Run()
+
+	invocations := binary.Invocations()
+	assert.Equal(t, [][]string{{"--version"}, {"--notes", "two words"}}, invocations)
+}
+
+func TestNew_DryRun(t *testing.T) {
+	t.Log("Testing fake binary with dryrun")
+	binary := New(t, Config{Results: "saddr\n1.1.1.1\n", DryRun: "tcp { source: 1.1.1.1 }\n"})
+
+	out, err := exec.Command(binary.Path, "--dryrun").Output()
+	if err != nil {
+		t.Error("Expected that error is not returned")
+	}
+	if !strings.HasPrefix(string(out), "tcp {") {
+		t.Error("Expected that dryrun output is written instead of results")
+	}
+}
+
+func TestNew_Interrupt(t *testing.T) {
+	t.Log("Testing fake binary with SIGINT while waiting")
+	binary := New(t, Config{Delay: time.Minute})
+
+	cmd := exec.Command(binary.Path)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Expected that fake binary is started: %v", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	_ = cmd.Process.Signal(os.Interrupt)
+
+	err := cmd.Wait()
+	t.Logf("Returned Error: %v", err)
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 130 {
+		t.Error("Expected that fake binary exits with code 130 after SIGINT")
+	}
+}
+
+// testing.TB must keep implementing TB, so that tests can pass *testing.T to New.
+var _ TB = testing.TB(nil)